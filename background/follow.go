@@ -0,0 +1,104 @@
+package background
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// как часто проверять лог на новый вывод
+const followInterval = 200 * time.Millisecond
+
+// Follow выводит лог запуска в w, начиная с offset, и ждет нового вывода,
+// пока запуск работает или пока не отменен ctx.
+func Follow(ctx context.Context, state *State, offset int64, w io.Writer) error {
+	file, err := os.Open(state.Log)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	for {
+		if _, err := io.Copy(w, file); err != nil {
+			return err
+		}
+
+		if !state.Running() {
+			// дочитываем то, что могло появиться перед завершением
+			_, err := io.Copy(w, file)
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(followInterval):
+		}
+	}
+}
+
+// TailOffset смещение, с которого в логе начинаются последние lines строк.
+func TailOffset(path string, lines int) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	const chunkSize = 64 * 1024
+
+	// идем с конца файла кусками, пока не наберем нужное количество переносов
+	end := info.Size()
+	count := 0
+	buf := make([]byte, chunkSize)
+	for end > 0 {
+		start := max(end-chunkSize, 0)
+		n, err := file.ReadAt(buf[:end-start], start)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return 0, err
+		}
+
+		chunk := buf[:n]
+		for i := len(chunk) - 1; i >= 0; i-- {
+			if chunk[i] != '\n' || start+int64(i) == info.Size()-1 {
+				continue
+			}
+
+			count++
+			if count == lines {
+				return start + int64(i) + 1, nil
+			}
+		}
+
+		end = start
+	}
+
+	return 0, nil
+}
+
+// Attach открывает ввод работающего запуска: все, что записано в него,
+// получают команды алиаса. У параллельных команд ввода нет.
+func Attach(state *State) (io.WriteCloser, error) {
+	if state.Input == "" {
+		return nil, fmt.Errorf("run %d does not accept input", state.ID)
+	}
+
+	input, err := openInput(state.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open input of run %d: %w", state.ID, err)
+	}
+
+	return input, nil
+}
@@ -0,0 +1,149 @@
+//go:build !windows
+
+package background_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/algrvvv/ali/background"
+)
+
+// startLogged запускает script с выводом в лог, как фоновый запуск ali.
+func startLogged(t *testing.T, script string) *background.State {
+	t.Helper()
+
+	log := filepath.Join(t.TempDir(), "1.log")
+	file, err := os.Create(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Stdout = file
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() { _ = cmd.Wait() }()
+	t.Cleanup(func() { _ = cmd.Process.Kill() })
+
+	return &background.State{ID: 1, Alias: "dev", Pid: cmd.Process.Pid, Log: log}
+}
+
+func TestFollow(t *testing.T) {
+	state := startLogged(t, "echo skip; sleep 0.3; echo one; sleep 0.3; echo two")
+
+	// пропускаем уже записанный вывод, как ali logs -n
+	time.Sleep(100 * time.Millisecond)
+	info, err := os.Stat(state.Log)
+	if err != nil || info.Size() == 0 {
+		t.Fatal("log is empty")
+	}
+	offset := info.Size()
+
+	var out bytes.Buffer
+	if err := background.Follow(context.Background(), state, offset, &out); err != nil {
+		t.Fatalf("ERROR: follow: %v", err)
+	}
+
+	if out.String() != "one\ntwo\n" {
+		t.Errorf("ERROR: want: %q; got: %q", "one\ntwo\n", out.String())
+	} else {
+		t.Logf("SUCCESS! follow waits for the run to exit")
+	}
+}
+
+func TestFollowCancel(t *testing.T) {
+	state := startLogged(t, "echo one; sleep 30")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	var out bytes.Buffer
+	if err := background.Follow(ctx, state, 0, &out); err != nil {
+		t.Fatalf("ERROR: follow: %v", err)
+	}
+
+	if out.String() != "one\n" {
+		t.Errorf("ERROR: want: %q; got: %q", "one\n", out.String())
+	} else {
+		t.Logf("SUCCESS! follow stops on cancel")
+	}
+}
+
+func TestTailOffset(t *testing.T) {
+	log := filepath.Join(t.TempDir(), "1.log")
+	if err := os.WriteFile(log, []byte("a\nb\nc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lines    int
+		expected int64
+	}{
+		{lines: 1, expected: 4},
+		{lines: 2, expected: 2},
+		{lines: 3, expected: 0},
+		{lines: 10, expected: 0},
+	}
+
+	for _, tt := range tests {
+		offset, err := background.TailOffset(log, tt.lines)
+		if err != nil || offset != tt.expected {
+			t.Errorf("ERROR: %d lines: want: %d; got: %d (%v)", tt.lines, tt.expected, offset, err)
+		} else {
+			t.Logf("SUCCESS! %d lines start at %d", tt.lines, offset)
+		}
+	}
+}
+
+func TestAttach(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "1.in")
+	if err := syscall.Mkfifo(input, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	state := &background.State{ID: 1, Input: input}
+
+	// никто не читает ввод - запуск уже завершился
+	if _, err := background.Attach(state); err == nil {
+		t.Errorf("ERROR: want: error when input is not read")
+	}
+
+	if _, err := background.Attach(&background.State{ID: 2}); err == nil {
+		t.Errorf("ERROR: want: error for run without input")
+	}
+
+	reader, err := os.OpenFile(input, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	writer, err := background.Attach(state)
+	if err != nil {
+		t.Fatalf("ERROR: attach: %v", err)
+	}
+
+	if _, err := writer.Write([]byte("hello\n")); err != nil {
+		t.Fatal(err)
+	}
+	writer.Close()
+
+	line, err := bufio.NewReader(reader).ReadString('\n')
+	if err != nil || line != "hello\n" {
+		t.Errorf("ERROR: want: %q; got: %q (%v)", "hello\n", line, err)
+	} else {
+		t.Logf("SUCCESS! input is passed to the run")
+	}
+}
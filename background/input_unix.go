@@ -0,0 +1,24 @@
+//go:build !windows
+
+package background
+
+import (
+	"os"
+	"syscall"
+)
+
+// createInput создает именованный канал для ввода запуска и открывает его на чтение.
+// NOTE: канал открыт и на запись, чтобы процесс не получил EOF, пока никто не подключен.
+func createInput(path string) (*os.File, error) {
+	if err := syscall.Mkfifo(path, 0o600); err != nil {
+		return nil, err
+	}
+
+	return os.OpenFile(path, os.O_RDWR, 0)
+}
+
+// openInput открывает ввод запуска на запись. Если запуск уже не читает канал,
+// открытие сразу завершается ошибкой, а не ждет читателя.
+func openInput(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package background
+
+import (
+	"errors"
+	"os"
+)
+
+// на windows нет именованных каналов в файловой системе, ввод в фоновый запуск не передается
+var errInputUnsupported = errors.New("input of background runs is not supported on windows")
+
+func createInput(string) (*os.File, error) {
+	return nil, errInputUnsupported
+}
+
+func openInput(string) (*os.File, error) {
+	return nil, errInputUnsupported
+}
@@ -0,0 +1,118 @@
+package background
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+// Start запускает ali с аргументами argv в фоне: в новой сессии,
+// с выводом в лог-файл. Запущенный ali сам выполняет алиас и по
+// завершении записывает результат в свое состояние.
+func Start(alias string, argv []string) (*State, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := reserveID(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &State{
+		ID:        id,
+		Alias:     alias,
+		Argv:      argv,
+		Dir:       cwd,
+		StartedAt: time.Now(),
+		Log:       filepath.Join(dir, strconv.Itoa(id)+".log"),
+	}
+
+	logFile, err := os.OpenFile(state.Log, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		os.Remove(statePath(dir, id))
+		return nil, err
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(executable, argv...)
+	cmd.Dir = cwd
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.Env = append(os.Environ(), EnvRunID+"="+strconv.Itoa(id))
+	utils.SetNewSession(cmd)
+
+	// NOTE: без канала ввода (windows) запуск просто получает пустой ввод
+	input := filepath.Join(dir, strconv.Itoa(id)+".in")
+	if inputFile, err := createInput(input); err == nil {
+		defer inputFile.Close()
+		cmd.Stdin = inputFile
+		state.Input = input
+	}
+
+	if err := cmd.Start(); err != nil {
+		os.Remove(statePath(dir, id))
+		os.Remove(state.Log)
+		if state.Input != "" {
+			os.Remove(state.Input)
+		}
+		return nil, err
+	}
+
+	// в новой сессии процесс - лидер своей группы процессов
+	state.Pid = cmd.Process.Pid
+	state.Pgid = cmd.Process.Pid
+	if err := state.save(dir); err != nil {
+		return nil, err
+	}
+
+	return state, cmd.Process.Release()
+}
+
+// reserveID выдает следующий id запуска, сразу создавая файл его состояния,
+// чтобы два одновременных запуска не получили один id.
+func reserveID(dir string) (int, error) {
+	states, err := List()
+	if err != nil {
+		return 0, err
+	}
+
+	id := 1
+	if len(states) > 0 {
+		id = states[len(states)-1].ID + 1
+	}
+
+	for ; ; id++ {
+		file, err := os.OpenFile(statePath(dir, id), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+
+		// NOTE: пока состояние не записано, файл содержит пустой объект
+		_, err = file.WriteString("{}")
+		file.Close()
+		return id, err
+	}
+}
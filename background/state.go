@@ -0,0 +1,218 @@
+package background
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/algrvvv/ali/logger"
+	"github.com/algrvvv/ali/utils"
+)
+
+// EnvRunID переменная окружения, по которой ali понимает, что запущен в фоне.
+const EnvRunID = "ALI_RUN_ID"
+
+// статусы фонового запуска
+const (
+	StatusRunning = "running"
+	StatusExited  = "exited"
+	// StatusLost процесс завершился, но не успел записать результат (например, после SIGKILL)
+	StatusLost = "lost"
+)
+
+// State состояние фонового запуска алиаса.
+type State struct {
+	ID    int      `json:"id"`
+	Alias string   `json:"alias"`
+	Argv  []string `json:"argv"`
+	Dir   string   `json:"dir"`
+	Pid   int      `json:"pid"`
+	// Pgid группа процессов запуска, ее завершает ali stop
+	Pgid      int       `json:"pgid"`
+	StartedAt time.Time `json:"started_at"`
+	Log       string    `json:"log"`
+	// Input именованный канал, через который ali attach передает ввод запуску
+	Input      string     `json:"input,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	ExitCode   *int       `json:"exit_code,omitempty"`
+}
+
+// Dir директория с состояниями фоновых запусков: ~/.ali/run.
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".ali", "run"), nil
+}
+
+func (s *State) Status() string {
+	switch {
+	case s.FinishedAt != nil:
+		return StatusExited
+	case utils.ProcessAlive(s.Pid):
+		return StatusRunning
+	default:
+		return StatusLost
+	}
+}
+
+func (s *State) Running() bool {
+	return s.Status() == StatusRunning
+}
+
+func statePath(dir string, id int) string {
+	return filepath.Join(dir, strconv.Itoa(id)+".json")
+}
+
+// save атомарно записывает состояние: через временный файл и переименование.
+func (s *State) save(dir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := statePath(dir, s.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, statePath(dir, s.ID))
+}
+
+// Finish записывает результат фонового запуска с указанным id.
+func Finish(id string, code int) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("invalid run id: %q", id)
+	}
+
+	state, err := load(statePath(dir, n))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	state.FinishedAt = &now
+	state.ExitCode = &code
+	return state.save(dir)
+}
+
+func load(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid run state %s: %w", path, err)
+	}
+
+	return &state, nil
+}
+
+// List все фоновые запуски, от старых к новым.
+func List() ([]*State, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var states []*State
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		// NOTE: битый файл (например, пустой после сбоя) не должен ломать ali ps и ali stop
+		state, err := load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			logger.SaveDebugf("skip background run state: %v", err)
+			continue
+		}
+
+		// NOTE: запуск, для которого только зарезервирован id, еще не стартовал
+		if state.Pid <= 0 {
+			continue
+		}
+		states = append(states, state)
+	}
+
+	sort.Slice(states, func(i, j int) bool { return states[i].ID < states[j].ID })
+	return states, nil
+}
+
+// Find ищет запуск по id или по имени алиаса. Для алиаса берется
+// последний работающий запуск, а если такого нет - просто последний.
+func Find(ref string) (*State, error) {
+	states, err := List()
+	if err != nil {
+		return nil, err
+	}
+
+	if id, err := strconv.Atoi(ref); err == nil {
+		for _, state := range states {
+			if state.ID == id {
+				return state, nil
+			}
+		}
+		return nil, fmt.Errorf("background run %d not found", id)
+	}
+
+	var found *State
+	for _, state := range states {
+		if state.Alias != ref {
+			continue
+		}
+
+		if found == nil || state.Running() || !found.Running() {
+			found = state
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("background runs of %q not found", ref)
+	}
+
+	return found, nil
+}
+
+// Remove удаляет состояние и лог завершенного запуска.
+func Remove(state *State) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(state.Log); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if state.Input != "" {
+		if err := os.Remove(state.Input); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return os.Remove(statePath(dir, state.ID))
+}
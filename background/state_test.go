@@ -0,0 +1,135 @@
+package background_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/algrvvv/ali/background"
+)
+
+// useRunDir переносит ~/.ali/run во временную директорию теста.
+func useRunDir(t *testing.T) string {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	dir, err := background.Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func writeState(t *testing.T, dir string, state background.State) {
+	t.Helper()
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, strconv.Itoa(state.ID)+".json"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir := useRunDir(t)
+
+	finished := time.Now()
+	code := 0
+	// NOTE: "работающий" запуск - сам процесс теста
+	running := os.Getpid()
+
+	writeState(t, dir, background.State{ID: 1, Alias: "dev", Pid: running, FinishedAt: &finished, ExitCode: &code})
+	writeState(t, dir, background.State{ID: 2, Alias: "dev", Pid: running})
+	writeState(t, dir, background.State{ID: 3, Alias: "dev", Pid: running, FinishedAt: &finished, ExitCode: &code})
+	writeState(t, dir, background.State{ID: 4, Alias: "api", Pid: running, FinishedAt: &finished, ExitCode: &code})
+	// зарезервированный, но еще не запущенный
+	writeState(t, dir, background.State{ID: 5, Alias: "web"})
+
+	tests := []struct {
+		ref      string
+		expected int
+	}{
+		{ref: "dev", expected: 2},
+		{ref: "3", expected: 3},
+		{ref: "api", expected: 4},
+		{ref: "web", expected: 0},
+		{ref: "5", expected: 0},
+		{ref: "9", expected: 0},
+	}
+
+	for _, tt := range tests {
+		state, err := background.Find(tt.ref)
+		switch {
+		case tt.expected == 0 && err == nil:
+			t.Errorf("ERROR: %s: want: error; got: run %d", tt.ref, state.ID)
+		case tt.expected != 0 && err != nil:
+			t.Errorf("ERROR: %s: want: run %d; got: %v", tt.ref, tt.expected, err)
+		case tt.expected != 0 && state.ID != tt.expected:
+			t.Errorf("ERROR: %s: want: run %d; got: run %d", tt.ref, tt.expected, state.ID)
+		default:
+			t.Logf("SUCCESS! %s: %v", tt.ref, err)
+		}
+	}
+}
+
+func TestRemove(t *testing.T) {
+	dir := useRunDir(t)
+
+	state := background.State{ID: 1, Alias: "dev", Pid: 1, Log: filepath.Join(dir, "1.log"), Input: filepath.Join(dir, "1.in")}
+	writeState(t, dir, state)
+	for _, path := range []string{state.Log, state.Input} {
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := background.Remove(&state); err != nil {
+		t.Fatalf("ERROR: remove: %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("ERROR: want: empty run dir; got: %d files", len(entries))
+	} else {
+		t.Logf("SUCCESS! state, log and input are removed")
+	}
+}
+
+func TestListCorrupt(t *testing.T) {
+	dir := useRunDir(t)
+
+	writeState(t, dir, background.State{ID: 1, Alias: "dev", Pid: os.Getpid()})
+	writeState(t, dir, background.State{ID: 3, Alias: "api", Pid: os.Getpid()})
+	// пустой и недописанный файлы состояния после сбоя
+	if err := os.WriteFile(filepath.Join(dir, "2.json"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "4.json"), []byte(`{"id": 4, "al`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	states, err := background.List()
+	if err != nil || len(states) != 2 || states[0].ID != 1 || states[1].ID != 3 {
+		t.Fatalf("ERROR: want: runs 1 and 3; got: %v (%v)", states, err)
+	}
+
+	if state, err := background.Find("api"); err != nil || state.ID != 3 {
+		t.Errorf("ERROR: want: run 3; got: %v (%v)", state, err)
+	} else {
+		t.Logf("SUCCESS! corrupt states are skipped")
+	}
+}
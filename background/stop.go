@@ -0,0 +1,34 @@
+package background
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+// Stop завершает все дерево процессов запуска: сначала мягко,
+// а если за timeout процессы не завершились - принудительно.
+func Stop(state *State, timeout time.Duration) error {
+	if state.Pgid <= 0 {
+		return fmt.Errorf("invalid process group of run %d", state.ID)
+	}
+
+	if !state.Running() {
+		return fmt.Errorf("run %d is not running", state.ID)
+	}
+
+	if err := utils.TerminateProcessGroup(state.Pgid); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if !utils.ProcessAlive(state.Pid) {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return utils.KillProcessGroup(state.Pgid)
+}
@@ -0,0 +1,113 @@
+//go:build !windows
+
+package background_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/algrvvv/ali/background"
+	"github.com/algrvvv/ali/utils"
+)
+
+// startGroup запускает script в своей группе процессов, как это делает ali --detach.
+// Возвращает состояние запуска и pid дочернего процесса, который script записал в файл.
+func startGroup(t *testing.T, script string) (*background.State, int) {
+	t.Helper()
+
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Env = append(os.Environ(), "PID_FILE="+pidFile)
+	utils.SetProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// NOTE: процесс нужно дождаться, иначе он останется зомби и будет считаться живым
+	go func() { _ = cmd.Wait() }()
+
+	var child int
+	for i := 0; i < 50 && child == 0; i++ {
+		data, _ := os.ReadFile(pidFile)
+		child, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+		time.Sleep(20 * time.Millisecond)
+	}
+	if child == 0 {
+		t.Fatal("child process is not started")
+	}
+
+	return &background.State{ID: 1, Alias: "dev", Pid: cmd.Process.Pid, Pgid: cmd.Process.Pid}, child
+}
+
+// alive учитывает зомби: осиротевший процесс может быть никем не дожидаем.
+func alive(pid int) bool {
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return utils.ProcessAlive(pid)
+	}
+
+	fields := strings.Fields(string(data[strings.LastIndexByte(string(data), ')')+1:]))
+	return len(fields) > 0 && fields[0] != "Z"
+}
+
+func waitGone(pid int) bool {
+	for i := 0; i < 50; i++ {
+		if !alive(pid) {
+			return true
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	return false
+}
+
+func TestStop(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{name: "term", script: `sleep 30 & echo $! > "$PID_FILE"; wait`},
+		// процессы игнорируют SIGTERM, после timeout их должен завершить SIGKILL
+		{name: "kill", script: `trap "" TERM; sleep 30 & echo $! > "$PID_FILE"; wait; wait`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, child := startGroup(t, tt.script)
+
+			if err := background.Stop(state, 300*time.Millisecond); err != nil {
+				t.Fatalf("ERROR: stop: %v", err)
+			}
+
+			if !waitGone(state.Pid) {
+				t.Errorf("ERROR: run process %d is alive", state.Pid)
+			}
+			if !waitGone(child) {
+				t.Errorf("ERROR: child process %d of the group is alive", child)
+				_ = utils.KillProcessGroup(state.Pgid)
+			}
+
+			t.Logf("SUCCESS! process group is stopped")
+		})
+	}
+}
+
+func TestStopInvalid(t *testing.T) {
+	if err := background.Stop(&background.State{ID: 1}, time.Second); err == nil {
+		t.Errorf("ERROR: want: error for run without process group")
+	}
+
+	finished := time.Now()
+	state := &background.State{ID: 2, Pid: os.Getpid(), Pgid: os.Getpid(), FinishedAt: &finished}
+	if err := background.Stop(state, time.Second); err == nil {
+		t.Errorf("ERROR: want: error for finished run")
+	}
+
+	t.Logf("SUCCESS! invalid runs are not stopped")
+}
@@ -0,0 +1,157 @@
+/*
+Copyright © 2024 algrvvv <alexandrgr25@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algrvvv/ali/logger"
+	"github.com/algrvvv/ali/sdk"
+	"github.com/algrvvv/ali/trust"
+	"github.com/algrvvv/ali/utils"
+)
+
+var (
+	allowYes bool
+
+	allowCmd = &cobra.Command{
+		Use:   "allow [file...]",
+		Short: "Review and trust local and included configs",
+		Long: `Review and trust local and included configs.
+ali doesn't load a local or included config until it is allowed: aliases from a freshly
+cloned repository can't run (or override global ones) before you look at them.
+A changed config has to be allowed again, ali allow shows which commands changed.
+Without arguments all skipped configs of the current directory are reviewed.`,
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) > 0 {
+				for _, file := range args {
+					if !utils.FileExists(file) {
+						fmt.Printf("config not found: %s\n", file)
+						os.Exit(1)
+					}
+					allowConfig(file)
+				}
+				return
+			}
+
+			files := pendingConfigs(aliConfig)
+			if len(files) == 0 {
+				fmt.Println("all configs are trusted")
+				return
+			}
+
+			// NOTE: после разрешения конфига подключаются его include, их тоже нужно разрешить
+			reviewed := make(map[string]bool)
+			for len(files) > 0 {
+				for _, file := range files {
+					reviewed[file] = true
+					allowConfig(file)
+				}
+
+				cfg, err := sdk.Load(loadOptions())
+				if err != nil {
+					logger.SaveDebugf("failed to reload config: %v", err)
+					return
+				}
+
+				files = slices.DeleteFunc(pendingConfigs(cfg), func(file string) bool {
+					return reviewed[file]
+				})
+			}
+		},
+	}
+)
+
+// pendingConfigs пропущенные конфиги, кроме запрещенных через ali deny.
+func pendingConfigs(cfg *sdk.Config) []string {
+	var files []string
+	for _, file := range cfg.UntrustedFiles {
+		if trustStore.Status(file) != trust.StatusDenied {
+			files = append(files, file)
+		}
+	}
+
+	return files
+}
+
+// allowConfig показывает изменения команд конфига и после подтверждения разрешает его.
+func allowConfig(file string) {
+	status := trustStore.Status(file)
+	if status == trust.StatusAllowed {
+		fmt.Printf("%s is already allowed\n", file)
+		return
+	}
+
+	fmt.Printf("%s (%s)\n", file, status)
+
+	entry, _ := trustStore.Entry(file)
+	commands, err := trust.Commands(file)
+	if err != nil {
+		fmt.Printf("  some aliases can't be read: %v\n", err)
+	}
+
+	changes := trust.Diff(entry.Commands, commands)
+	for _, change := range changes {
+		for _, command := range change.Old {
+			fmt.Println(utils.Colorize("  - "+change.Alias+": "+command, "red"))
+		}
+		for _, command := range change.New {
+			fmt.Println(utils.Colorize("  + "+change.Alias+": "+command, "green"))
+		}
+	}
+	if len(changes) == 0 {
+		fmt.Println("  commands not changed (vars, env or other settings may differ)")
+	}
+
+	if !allowYes && !confirm("allow?") {
+		fmt.Println("skipped")
+		return
+	}
+
+	if err := trustStore.Allow(file); err != nil {
+		utils.PrintError("failed to allow config", err)
+		os.Exit(1)
+	}
+	fmt.Println("allowed")
+}
+
+var stdinReader = bufio.NewReader(os.Stdin)
+
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+
+	answer, _ := stdinReader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
+
+func init() {
+	rootCmd.AddCommand(allowCmd)
+	allowCmd.Flags().BoolVarP(&allowYes, "yes", "y", false, "allow without confirmation")
+}
@@ -0,0 +1,91 @@
+/*
+Copyright © 2024 algrvvv <alexandrgr25@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algrvvv/ali/background"
+	"github.com/algrvvv/ali/logger"
+	"github.com/algrvvv/ali/utils"
+)
+
+// сколько последних строк показать при подключении
+const attachTail = 50
+
+var attachCmd = &cobra.Command{
+	Use:     "attach <alias|id>",
+	Short:   "Attach to alias running in background",
+	Example: "ali attach dev - show last output of dev, follow it and pass your input to it",
+	Args:    cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		state, err := background.Find(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if !state.Running() {
+			fmt.Printf("run %d of %s is not running; use ali logs %d\n", state.ID, state.Alias, state.ID)
+			os.Exit(1)
+		}
+
+		offset, err := background.TailOffset(state.Log, attachTail)
+		if err != nil {
+			utils.PrintError("failed to read logs", err)
+			os.Exit(1)
+		}
+
+		// NOTE: без ввода (windows, запуски старых версий) attach только показывает вывод
+		input, err := background.Attach(state)
+		if err != nil {
+			logger.SaveDebugf("attach without input: %v", err)
+		} else {
+			defer input.Close()
+			go func() {
+				_, _ = io.Copy(input, os.Stdin)
+			}()
+		}
+
+		fmt.Printf("attached to %s%s%s (id %d); press Ctrl+C to detach, ali stop %d to stop it\n",
+			color, state.Alias, resetColor, state.ID, state.ID)
+
+		if err := showLogs(state, offset, true); err != nil {
+			utils.PrintError("failed to read logs", err)
+			os.Exit(1)
+		}
+
+		if !state.Running() {
+			if state, err = background.Find(fmt.Sprint(state.ID)); err == nil && state.ExitCode != nil {
+				fmt.Printf("%s exited with code %d\n", state.Alias, *state.ExitCode)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(attachCmd)
+}
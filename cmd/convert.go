@@ -0,0 +1,96 @@
+/*
+Copyright © 2024 algrvvv <alexandrgr25@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algrvvv/ali/logger"
+	"github.com/algrvvv/ali/utils"
+)
+
+var (
+	convertTo     string
+	convertOutput string
+
+	convertCmd = &cobra.Command{
+		Use:   "convert [file]",
+		Short: "Convert config to yaml, toml or json",
+		Long: `Convert config to another format. By default the nearest local config
+is converted and the result is printed to stdout; use -o to save it to a file.
+Comments of the source config are not kept.`,
+		Example: `  ali convert --to toml -o .ali.toml
+  ali convert ~/.ali/config.yml --to json`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			types := []string{utils.YamlConfigurationType, utils.TomlConfigurationType, utils.JsonConfigurationType}
+			if !slices.Contains(types, convertTo) {
+				fmt.Printf("%v: %q (use: yaml, toml, json)\n", utils.ErrUnsupportedConfigType, convertTo)
+				os.Exit(1)
+			}
+
+			var file string
+			if len(args) > 0 {
+				file = args[0]
+			} else if len(localConfigFiles) > 0 {
+				file = localConfigFiles[0]
+			} else {
+				fmt.Println("local config not found (pass config file to convert)")
+				os.Exit(1)
+			}
+			logger.SaveDebugf("convert %s to %s", file, convertTo)
+
+			config, err := utils.ReadConfigMap(file)
+			if err != nil {
+				utils.PrintError("failed to read config", err)
+				os.Exit(1)
+			}
+
+			data, err := utils.EncodeConfig(config, convertTo)
+			if err != nil {
+				utils.PrintError("failed to convert config", err)
+				os.Exit(1)
+			}
+
+			if convertOutput == "" {
+				fmt.Print(string(data))
+				return
+			}
+
+			if err := os.WriteFile(convertOutput, data, 0o600); err != nil {
+				utils.PrintError("failed to save config", err)
+				os.Exit(1)
+			}
+			fmt.Printf("%s converted to %s\n", file, convertOutput)
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(convertCmd)
+	convertCmd.Flags().StringVarP(&convertTo, "to", "t", utils.YamlConfigurationType, "target format: yaml, toml or json")
+	convertCmd.Flags().StringVarP(&convertOutput, "output", "o", "", "save result to file instead of stdout")
+}
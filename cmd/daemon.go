@@ -0,0 +1,321 @@
+/*
+Copyright © 2024 algrvvv <alexandrgr25@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/algrvvv/ali/logger"
+	"github.com/algrvvv/ali/schedule"
+	"github.com/algrvvv/ali/sdk"
+	"github.com/algrvvv/ali/trust"
+	"github.com/algrvvv/ali/utils"
+)
+
+// пауза после изменения конфига перед перезагрузкой: редакторы пишут файл в несколько приемов
+const reloadDelay = 500 * time.Millisecond
+
+var (
+	daemonList bool
+
+	daemonCmd = &cobra.Command{
+		Use:   "daemon",
+		Short: "Run aliases by schedule",
+		Long: "Run aliases that have schedule in the config of the current directory.\n" +
+			"The config is reloaded when config files change.",
+		Example: "ali daemon - run scheduled aliases\nali daemon --list - show when aliases will run",
+		Args:    cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			dir, err := os.Getwd()
+			utils.CheckError(err)
+
+			d, err := schedule.NewDaemon(dir, runScheduled, daemonLogf)
+			if err != nil {
+				utils.PrintError("failed to start daemon", err)
+				os.Exit(1)
+			}
+
+			jobs, err := scheduledJobs(aliConfig)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			if daemonList {
+				if len(jobs) == 0 {
+					fmt.Println("no aliases with schedule")
+				}
+				d.Plan(jobs)
+				return
+			}
+
+			unlock, err := lockDaemon(dir)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			defer unlock()
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			if len(jobs) == 0 {
+				daemonLogf("no aliases with schedule; waiting for config changes")
+			}
+			d.SetJobs(jobs)
+
+			go watchConfig(ctx, d, aliConfig)
+
+			daemonLogf("daemon started in %s", dir)
+			d.Loop(ctx)
+			daemonLogf("daemon stopped")
+		},
+	}
+)
+
+func daemonLogf(format string, args ...any) {
+	fmt.Printf("%s %s\n", time.Now().Format(time.DateTime), fmt.Sprintf(format, args...))
+	logger.SaveDebugf("daemon: "+format, args...)
+}
+
+// scheduledJobs алиасы конфигурации, у которых задано расписание.
+func scheduledJobs(cfg *sdk.Config) ([]schedule.Job, error) {
+	// NOTE: демон не должен запускать расписание из наполовину разобранного конфига
+	aliases, err := cfg.Aliases()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	var jobs []schedule.Job
+	for name, entry := range aliases {
+		if entry.Schedule == nil {
+			continue
+		}
+
+		if err := entry.Schedule.Prepare(); err != nil {
+			return nil, fmt.Errorf("alias %q: %w", name, err)
+		}
+
+		jobs = append(jobs, schedule.Job{Alias: name, Schedule: entry.Schedule})
+	}
+
+	return jobs, nil
+}
+
+// runScheduled запускает алиас отдельным процессом ali: так он выполняется
+// ровно так же, как из терминала, и попадает в историю.
+func runScheduled(alias string) int {
+	executable, err := os.Executable()
+	if err != nil {
+		daemonLogf("%s: failed to get ali executable: %v", alias, err)
+		return -1
+	}
+
+	cmd := exec.Command(executable, scheduledArgs(alias, localEnv, profile)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return utils.ExitCode(cmd.Run())
+}
+
+// scheduledArgs аргументы ali для запуска алиаса по расписанию: флаги демона,
+// влияющие на загрузку конфигурации, передаются запуску. ALI_PROFILE он наследует сам.
+func scheduledArgs(alias string, local bool, profile string) []string {
+	var args []string
+	if local {
+		args = append(args, "-L")
+	}
+	if profile != "" {
+		args = append(args, "--profile", profile)
+	}
+
+	return append(args, alias)
+}
+
+// configFiles файлы конфигурации, при изменении которых демон перечитывает расписание.
+func configFiles(cfg *sdk.Config) []string {
+	// локального конфига может еще не быть, но за его появлением тоже следим
+	files := append([]string{cfg.GlobalFile, localConfig}, cfg.LocalFiles...)
+
+	files = append(files, cfg.IncludedFiles...)
+
+	// ali allow и ali deny меняют набор загружаемых конфигов
+	if path, err := trust.Path(); err == nil {
+		files = append(files, path)
+	}
+	files = append(files, cfg.UntrustedFiles...)
+
+	out := make([]string, 0, len(files))
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+
+		if abs, err := filepath.Abs(file); err == nil && !slices.Contains(out, abs) {
+			out = append(out, abs)
+		}
+	}
+
+	return out
+}
+
+// watchConfig следит за файлами конфигурации и перезагружает расписание.
+// Следим за директориями, а не файлами: редакторы часто сохраняют файл через переименование.
+func watchConfig(ctx context.Context, d *schedule.Daemon, cfg *sdk.Config) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		daemonLogf("failed to watch config files: %v", err)
+		return
+	}
+	defer watcher.Close()
+
+	files := configFiles(cfg)
+	for _, file := range files {
+		if err := watcher.Add(filepath.Dir(file)); err != nil {
+			logger.SaveDebugf("daemon: failed to watch %s: %v", file, err)
+		}
+	}
+
+	var reload <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-watcher.Errors:
+			logger.SaveDebugf("daemon: watcher error: %v", err)
+		case event := <-watcher.Events:
+			if slices.Contains(files, event.Name) && !event.Has(fsnotify.Chmod) {
+				reload = time.After(reloadDelay)
+			}
+		case <-reload:
+			reload = nil
+
+			// NOTE: при ошибке продолжаем работать по прежнему расписанию
+			loaded, jobs, err := reloadConfig(files)
+			if err != nil {
+				daemonLogf("config is not reloaded: %v", err)
+				continue
+			}
+
+			cfg = loaded
+			daemonLogf("config reloaded")
+			d.SetJobs(jobs)
+
+			// список include мог поменяться
+			for _, file := range configFiles(cfg) {
+				if !slices.Contains(files, file) {
+					files = append(files, file)
+					_ = watcher.Add(filepath.Dir(file))
+				}
+			}
+		}
+	}
+}
+
+// reloadConfig заново читает конфигурацию в отдельный экземпляр, не трогая текущий.
+// Сначала каждый файл проверяется отдельно: при загрузке битый локальный конфиг
+// пропускается молча, и демон потерял бы его расписание.
+func reloadConfig(files []string) (*sdk.Config, []schedule.Job, error) {
+	for _, file := range files {
+		if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		v := viper.New()
+		utils.UseConfigFile(v, file)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// ali allow и ali deny меняют доверенные конфиги
+	path, err := trust.Path()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	store, err := trust.Load(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read trust database %s: %w", path, err)
+	}
+
+	opts := loadOptions()
+	opts.Trusted = store.Trusted
+
+	cfg, err := sdk.Load(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	jobs, err := scheduledJobs(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, jobs, nil
+}
+
+// lockDaemon не дает запустить второй демон в той же директории.
+func lockDaemon(dir string) (func(), error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha1.Sum([]byte(dir))
+	path := filepath.Join(home, ".ali", "daemon-"+hex.EncodeToString(sum[:4])+".pid")
+
+	if data, err := os.ReadFile(path); err == nil {
+		if pid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && pid != os.Getpid() && utils.ProcessAlive(pid) {
+			return nil, fmt.Errorf("daemon is already running in %s (pid %d)", dir, pid)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())), 0o644); err != nil {
+		return nil, err
+	}
+
+	return func() { os.Remove(path) }, nil
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+
+	daemonCmd.Flags().BoolVar(&daemonList, "list", false, "show aliases with schedule and their next run")
+}
@@ -0,0 +1,30 @@
+package cmd_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/algrvvv/ali/cmd"
+)
+
+func TestScheduledArgs(t *testing.T) {
+	tests := []struct {
+		local    bool
+		profile  string
+		expected []string
+	}{
+		{expected: []string{"backup"}},
+		{local: true, expected: []string{"-L", "backup"}},
+		{profile: "prod", expected: []string{"--profile", "prod", "backup"}},
+		{local: true, profile: "prod", expected: []string{"-L", "--profile", "prod", "backup"}},
+	}
+
+	for _, test := range tests {
+		got := cmd.ScheduledArgs("backup", test.local, test.profile)
+		if !slices.Equal(got, test.expected) {
+			t.Errorf("ERROR: want: %v; got: %v", test.expected, got)
+		} else {
+			t.Logf("SUCCESS! got: %v", got)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright © 2024 algrvvv <alexandrgr25@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+var denyCmd = &cobra.Command{
+	Use:   "deny [file...]",
+	Short: "Stop trusting configs",
+	Long: `Stop trusting configs: ali skips them without warnings until they are allowed
+again with ali allow <file>. Without arguments the nearest local config is denied.`,
+	Run: func(_ *cobra.Command, args []string) {
+		files := args
+		if len(files) == 0 {
+			found, err := utils.FindLocalConfigs(".", localConfig, nil)
+			if err != nil || len(found) == 0 {
+				fmt.Println("local config not found")
+				os.Exit(1)
+			}
+			files = found[:1]
+		}
+
+		for _, file := range files {
+			if err := trustStore.Deny(file); err != nil {
+				utils.PrintError("failed to deny config", err)
+				os.Exit(1)
+			}
+			fmt.Printf("denied %s\n", file)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(denyCmd)
+}
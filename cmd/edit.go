@@ -51,12 +51,20 @@ var (
 			utils.CheckError(err)
 
 			var path string
+			// NOTE: свой новый или уже доверенный конфиг после редактирования разрешаем сразу
+			trusted := true
 			if isLocal {
 				var dir string
 				dir, err = os.Getwd()
 				utils.CheckError(err)
 
-				path = filepath.Join(dir, ".ali")
+				var ok bool
+				if path, ok = utils.FindConfigIn(dir, localConfig); !ok {
+					path = filepath.Join(dir, localConfig)
+				}
+				trusted = !ok || trustStore.Trusted(path)
+			} else if globalConfigFile != "" {
+				path = globalConfigFile
 			} else {
 				path = filepath.Join(home, ".ali/config.yml")
 			}
@@ -69,6 +77,10 @@ var (
 
 			err = cmd.Run()
 			utils.CheckError(err)
+
+			if isLocal && trusted && utils.FileExists(path) {
+				utils.CheckError(trustStore.Allow(path))
+			}
 		},
 	}
 )
@@ -0,0 +1,6 @@
+package cmd
+
+var (
+	ScheduledArgs   = scheduledArgs
+	UntrustedSource = untrustedSource
+)
@@ -0,0 +1,140 @@
+/*
+Copyright © 2024 algrvvv <alexandrgr25@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algrvvv/ali/history"
+	"github.com/algrvvv/ali/utils"
+)
+
+var (
+	historyAlias  string
+	historyFailed bool
+	historyDir    string
+	historySince  string
+	historyLimit  int
+	historyJSON   bool
+
+	historyCmd = &cobra.Command{
+		Use:   "history",
+		Short: "Show history of alias runs",
+		Example: "ali history - last runs\n" +
+			"ali history --failed --since 24h - failed runs for the last day\n" +
+			"ali history --dir . --alias build --json - runs of build in current project as json",
+		Args: cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			filter := history.Filter{
+				Alias:  historyAlias,
+				Failed: historyFailed,
+				Limit:  historyLimit,
+			}
+
+			if historyDir != "" {
+				dir, err := filepath.Abs(historyDir)
+				utils.CheckError(err)
+				filter.Dir = dir
+			}
+
+			if historySince != "" {
+				since, err := history.ParseSince(historySince, time.Now())
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				filter.Since = since
+			}
+
+			records, err := history.Load()
+			if err != nil {
+				utils.PrintError("failed to load history", err)
+				os.Exit(1)
+			}
+			records = history.FilterRecords(records, filter)
+
+			if historyJSON {
+				if records == nil {
+					records = []history.Record{}
+				}
+
+				data, err := json.MarshalIndent(records, "", "  ")
+				utils.CheckError(err)
+				fmt.Println(string(data))
+				return
+			}
+
+			printHistory(records)
+		},
+	}
+)
+
+func printHistory(records []history.Record) {
+	if len(records) == 0 {
+		fmt.Println("history is empty")
+		return
+	}
+
+	home, _ := os.UserHomeDir()
+
+	fmt.Printf("%-6s %-19s %-10s %-5s %-30s %s\n", "ID", "STARTED", "DURATION", "CODE", "DIR", "COMMAND")
+	for _, record := range records {
+		code := fmt.Sprintf("%-5d", record.ExitCode)
+		if record.Failed() {
+			code = utils.Colorize(code, "red")
+		} else {
+			code = utils.Colorize(code, "green")
+		}
+
+		dir := record.Dir
+		if home != "" && strings.HasPrefix(dir, home) {
+			dir = "~" + strings.TrimPrefix(dir, home)
+		}
+
+		fmt.Printf("%-6d %-19s %-10s %s %-30s %s\n",
+			record.ID,
+			record.StartedAt.Local().Format(time.DateTime),
+			record.Duration.Round(time.Millisecond),
+			code,
+			utils.TruncateString(dir, 30),
+			record.CommandLine(),
+		)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().StringVarP(&historyAlias, "alias", "a", "", "show runs of alias")
+	historyCmd.Flags().BoolVarP(&historyFailed, "failed", "f", false, "show only failed runs")
+	historyCmd.Flags().StringVarP(&historyDir, "dir", "d", "", "show runs in dir and its subdirs")
+	historyCmd.Flags().StringVarP(&historySince, "since", "s", "", "show runs since duration (24h, 7d) or date (2006-01-02)")
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 20, "count of last runs to show (0 - all)")
+	historyCmd.Flags().BoolVar(&historyJSON, "json", false, "print runs as json")
+}
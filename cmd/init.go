@@ -44,31 +44,35 @@ var (
 			utils.CheckError(err)
 			logger.SaveDebugf("got dir: %s", dir)
 
-			if utils.FileExists(localConfig) {
-				fmt.Println("local config already exists")
+			if file, ok := utils.FindConfigIn(".", localConfig); ok {
+				fmt.Printf("local config already exists: %s\n", file)
 				return
 			}
 
-			f, err := os.OpenFile(localConfig, os.O_CREATE|os.O_RDWR, 0o600)
-			utils.CheckError(err)
-
-			defer f.Close()
-			fmt.Println("local config file initialized")
-
+			var name, content string
 			switch configFormat {
 			case utils.YamlConfigurationType:
-				_, err = f.WriteString("aliases:\n")
+				name, content = localConfig, "aliases:\n"
 			case utils.JsonConfigurationType:
-				_, err = f.WriteString("{\n\t\"aliases\": {}\n}")
+				name, content = localConfig+".json", "{\n\t\"aliases\": {}\n}\n"
 			case utils.TomlConfigurationType:
-				_, err = f.WriteString("[aliases]\n")
+				name, content = localConfig+".toml", "[aliases]\n"
 			default:
 				fmt.Println(utils.ErrUnsupportedConfigType)
 				return
 			}
 
+			f, err := os.OpenFile(name, os.O_CREATE|os.O_RDWR, 0o600)
+			utils.CheckError(err)
+
+			defer f.Close()
+
+			_, err = f.WriteString(content)
 			utils.CheckError(err)
 			utils.CheckError(f.Close())
+			utils.CheckError(trustStore.Allow(name))
+
+			fmt.Println("local config file initialized:", name)
 
 			// files, err := os.ReadDir(dir)
 			// utils.CheckError(err)
@@ -97,6 +101,5 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 
-	// NOTE: больше не поддерживается
-	// initCmd.Flags().StringVarP(&configFormat, "format", "F", utils.YamlConfigurationType, "new local config type")
+	initCmd.Flags().StringVarP(&configFormat, "format", "F", utils.YamlConfigurationType, "new local config type: yaml, toml or json")
 }
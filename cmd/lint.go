@@ -0,0 +1,84 @@
+/*
+Copyright © 2024 algrvvv <alexandrgr25@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algrvvv/ali/utils"
+	"github.com/algrvvv/ali/validate"
+)
+
+var (
+	lintJSON bool
+
+	lintCmd = &cobra.Command{
+		Use:   "lint",
+		Short: "Find unused vars and env, undefined vars and overridden aliases",
+		Long: `Find problems that don't break the config but are likely mistakes:
+vars that no command uses, env keys that commands never reference,
+{{var}} without a value in vars and local aliases overriding global ones.
+Use ali validate for errors in the config itself.`,
+		Args: cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			global := globalConfigFile
+			if localEnv {
+				global = ""
+			}
+			issues := validate.Lint(global, loadedConfigFiles())
+
+			if lintJSON {
+				if issues == nil {
+					issues = []validate.Issue{}
+				}
+
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(issues); err != nil {
+					utils.PrintError("failed to encode issues", err)
+					os.Exit(1)
+				}
+			} else {
+				for _, issue := range issues {
+					fmt.Println(issue)
+				}
+
+				if len(issues) == 0 {
+					fmt.Println("no problems found")
+				}
+			}
+
+			if len(issues) > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().BoolVar(&lintJSON, "json", false, "print problems as json")
+}
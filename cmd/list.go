@@ -23,8 +23,10 @@ package cmd
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -74,9 +76,12 @@ func printEnvs(search string) {
 	logger.SaveDebugf("print envs")
 	fmt.Println("Available Envs:")
 
-	envs := viper.GetStringMap("env")
+	envs := maps.Clone(viper.GetStringMap("env"))
 
-	aliases := utils.LoadAliases(viper.GetViper())
+	aliases, err := utils.LoadAliases(viper.GetViper())
+	if err != nil {
+		logger.SaveDebugf("failed to load some aliases: %v", err)
+	}
 	for alias, entry := range aliases {
 		for name, value := range entry.Env {
 			key := fmt.Sprintf("%s (%s)", name, alias)
@@ -160,7 +165,7 @@ func searchInEnvs(search, envName string) bool {
 }
 
 func printVars(search string) {
-	vars, err := utils.GetVars()
+	vars, err := aliConfig.Vars()
 	if err != nil {
 		fmt.Println("failed to get vars")
 		logger.SaveDebugf("failed to get vars: %s", err)
@@ -237,64 +242,137 @@ func searchInVars(search, varName, varValue string) bool {
 
 func printAliases(search string) {
 	fmt.Println("Available Aliases:")
-	aliases := utils.LoadAliases(viper.GetViper())
+	aliases, err := utils.LoadAliases(viper.GetViper())
+	if err != nil {
+		logger.SaveDebugf("failed to load some aliases: %v", err)
+	}
 
 	if tablePrint {
 		aliasTablePrint(aliases, search)
 		return
 	}
-	aliasFullPrint(aliases, search)
+	aliasFullPrint(aliases, utils.LoadGroups(viper.GetViper()), search)
 }
 
-func aliasFullPrint(aliases map[string]utils.AliasEntry, search string) {
-	var count int
-	for alias, entry := range aliases {
-		if !searchInAlias(search, alias, entry) {
-			continue
-		}
+// aliasNode алиас или группа в дереве ali list.
+type aliasNode struct {
+	name     string
+	entry    *utils.AliasEntry
+	group    *utils.AliasGroup
+	children []*aliasNode
+}
 
-		prefix := "  └── "
-		if count != len(aliases)-1 {
-			prefix = "  ├── "
+// aliasTree раскладывает алиасы, подходящие под поиск, по группам.
+func aliasTree(
+	aliases map[string]utils.AliasEntry,
+	groups map[string]utils.AliasGroup,
+	search string,
+) []*aliasNode {
+	root := &aliasNode{}
+	nodes := map[string]*aliasNode{"": root}
+
+	var node func(name string) *aliasNode
+	node = func(name string) *aliasNode {
+		if n, ok := nodes[name]; ok {
+			return n
 		}
-		count++
 
-		if entry.Desc == "" {
-			entry.Desc = "no desc"
+		parent, short := "", name
+		if i := strings.LastIndex(name, utils.GroupSeparator); i >= 0 {
+			parent, short = name[:i], name[i+1:]
 		}
-		entry.Desc = fmt.Sprintf("%s%s%s", utils.Colors["yellow"], entry.Desc, resetColor)
 
-		clr := color
-		if entry.Parallel {
-			clr = utils.Colors["cyan"]
+		n := &aliasNode{name: short}
+		if group, ok := groups[name]; ok {
+			n.group = &group
 		}
+		nodes[name] = n
 
-		if len(entry.Aliases) > 0 {
-			alias += fmt.Sprintf(" %s(%s)%s", utils.Colors["orange"], strings.Join(entry.Aliases, ", "), resetColor)
+		p := node(parent)
+		p.children = append(p.children, n)
+		return n
+	}
+
+	names := slices.Sorted(maps.Keys(aliases))
+	for _, name := range names {
+		entry := aliases[name]
+		if !searchInAlias(search, name, entry) {
+			continue
 		}
+		node(name).entry = &entry
+	}
 
-		fmt.Printf("%s%s%s%s -> %s\n", prefix, clr, alias, resetColor, entry.Desc)
+	return root.children
+}
+
+func aliasFullPrint(
+	aliases map[string]utils.AliasEntry,
+	groups map[string]utils.AliasGroup,
+	search string,
+) {
+	printAliasNodes(aliasTree(aliases, groups, search), "  ")
+}
 
-		for i, c := range entry.Cmds {
-			prefix := "     └──"
-			if i != len(entry.Cmds)-1 {
-				prefix = "     ├──"
+func printAliasNodes(nodes []*aliasNode, indent string) {
+	for i, node := range nodes {
+		prefix, childIndent := "├── ", "│   "
+		if i == len(nodes)-1 {
+			prefix, childIndent = "└── ", "    "
+		}
+
+		if node.entry == nil {
+			desc := "group"
+			if node.group != nil && node.group.Desc != "" {
+				desc = node.group.Desc
 			}
 
-			// добавляем выделение переменных (vars)
-			re := regexp.MustCompile(`\{\{\w+\}\}`)
-			c = re.ReplaceAllStringFunc(c, func(varStr string) string {
-				return utils.Colors["lime"] + varStr + resetColor
-			})
+			fmt.Printf("%s%s%s%s%s -> %s%s%s\n",
+				indent, prefix, utils.Colors["magenta"], node.name, resetColor,
+				utils.Colors["yellow"], desc, resetColor)
+			printAliasNodes(node.children, indent+childIndent)
+			continue
+		}
+
+		printAliasEntry(node.name, *node.entry, indent+prefix, indent+"   ")
+	}
+}
+
+func printAliasEntry(alias string, entry utils.AliasEntry, prefix, cmdIndent string) {
+	if entry.Desc == "" {
+		entry.Desc = "no desc"
+	}
+	entry.Desc = fmt.Sprintf("%s%s%s", utils.Colors["yellow"], entry.Desc, resetColor)
+
+	clr := color
+	if entry.Parallel {
+		clr = utils.Colors["cyan"]
+	}
 
-			// добавляем подсвечивание переменных окружения (env)
-			re = regexp.MustCompile(`\$(\w+)`)
-			c = re.ReplaceAllStringFunc(c, func(envStr string) string {
-				return utils.Colors["red"] + envStr + resetColor
-			})
+	if len(entry.Aliases) > 0 {
+		alias += fmt.Sprintf(" %s(%s)%s", utils.Colors["orange"], strings.Join(entry.Aliases, ", "), resetColor)
+	}
+
+	fmt.Printf("%s%s%s%s -> %s\n", prefix, clr, alias, resetColor, entry.Desc)
 
-			fmt.Printf("%s %s\n", prefix, c)
+	for i, c := range entry.Cmds {
+		prefix := cmdIndent + "└──"
+		if i != len(entry.Cmds)-1 {
+			prefix = cmdIndent + "├──"
 		}
+
+		// добавляем выделение переменных (vars)
+		re := regexp.MustCompile(`\{\{\w+\}\}`)
+		c = re.ReplaceAllStringFunc(c, func(varStr string) string {
+			return utils.Colors["lime"] + varStr + resetColor
+		})
+
+		// добавляем подсвечивание переменных окружения (env)
+		re = regexp.MustCompile(`\$(\w+)`)
+		c = re.ReplaceAllStringFunc(c, func(envStr string) string {
+			return utils.Colors["red"] + envStr + resetColor
+		})
+
+		fmt.Printf("%s %s\n", prefix, c)
 	}
 }
 
@@ -307,7 +385,8 @@ func aliasTablePrint(aliases map[string]utils.AliasEntry, search string) {
 	)
 	fmt.Printf("+%s+%s+%s+\n", strings.Repeat("-", 22), strings.Repeat("-", 42), strings.Repeat("-", 30))
 
-	for alias, entry := range aliases {
+	for _, alias := range slices.Sorted(maps.Keys(aliases)) {
+		entry := aliases[alias]
 		if !searchInAlias(search, alias, entry) {
 			continue
 		}
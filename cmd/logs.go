@@ -0,0 +1,98 @@
+/*
+Copyright © 2024 algrvvv <alexandrgr25@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algrvvv/ali/background"
+	"github.com/algrvvv/ali/utils"
+)
+
+var (
+	logsFollow bool
+	logsTail   int
+
+	logsCmd = &cobra.Command{
+		Use:     "logs <alias|id>",
+		Short:   "Show output of alias running in background",
+		Example: "ali logs dev - output of last run of dev\nali logs -f 3 - follow output of run with id 3",
+		Args:    cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			state, err := background.Find(args[0])
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			var offset int64
+			if logsTail > 0 {
+				offset, err = background.TailOffset(state.Log, logsTail)
+				if err != nil {
+					utils.PrintError("failed to read logs", err)
+					os.Exit(1)
+				}
+			}
+
+			if err := showLogs(state, offset, logsFollow); err != nil {
+				utils.PrintError("failed to read logs", err)
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+// showLogs выводит лог запуска, а с follow ждет нового вывода до завершения запуска или Ctrl+C.
+func showLogs(state *background.State, offset int64, follow bool) error {
+	if !follow {
+		file, err := os.Open(state.Log)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		if _, err := file.Seek(offset, 0); err != nil {
+			return err
+		}
+
+		_, err = file.WriteTo(os.Stdout)
+		return err
+	}
+
+	// NOTE: Ctrl+C только прекращает вывод, сам запуск продолжает работать
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	return background.Follow(ctx, state, offset, os.Stdout)
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "wait for new output")
+	logsCmd.Flags().IntVarP(&logsTail, "tail", "n", 0, "show only last lines")
+}
@@ -0,0 +1,122 @@
+/*
+Copyright © 2024 algrvvv <alexandrgr25@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algrvvv/ali/background"
+	"github.com/algrvvv/ali/history"
+	"github.com/algrvvv/ali/utils"
+)
+
+var (
+	psAll   bool
+	psPrune bool
+
+	psCmd = &cobra.Command{
+		Use:     "ps",
+		Short:   "List aliases running in background",
+		Example: "ali ps - running aliases\nali ps -a - including finished runs\nali ps --prune - remove finished runs and their logs",
+		Args:    cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			states, err := background.List()
+			if err != nil {
+				utils.PrintError("failed to get background runs", err)
+				os.Exit(1)
+			}
+
+			if psPrune {
+				var removed int
+				for _, state := range states {
+					if state.Running() {
+						continue
+					}
+
+					if err := background.Remove(state); err != nil {
+						utils.PrintError("failed to remove background run", err)
+						os.Exit(1)
+					}
+					removed++
+				}
+
+				fmt.Printf("removed %d finished runs\n", removed)
+				return
+			}
+
+			printRuns(states)
+		},
+	}
+)
+
+func printRuns(states []*background.State) {
+	var printed bool
+	for _, state := range states {
+		status := state.Status()
+		if !psAll && status != background.StatusRunning {
+			continue
+		}
+
+		if !printed {
+			fmt.Printf("%-5s %-20s %-8s %-12s %-19s %s\n", "ID", "ALIAS", "PID", "STATUS", "STARTED", "COMMAND")
+			printed = true
+		}
+
+		switch status {
+		case background.StatusRunning:
+			status = utils.Colorize(fmt.Sprintf("%-12s", "up "+time.Since(state.StartedAt).Round(time.Second).String()), "green")
+		case background.StatusExited:
+			clr := "green"
+			if *state.ExitCode != 0 {
+				clr = "red"
+			}
+			status = utils.Colorize(fmt.Sprintf("%-12s", fmt.Sprintf("exited (%d)", *state.ExitCode)), clr)
+		default:
+			status = utils.Colorize(fmt.Sprintf("%-12s", status), "yellow")
+		}
+
+		record := &history.Record{Argv: state.Argv}
+		fmt.Printf("%-5d %s%-20s%s %-8d %s %-19s %s\n",
+			state.ID,
+			color, utils.TruncateString(state.Alias, 20), resetColor,
+			state.Pid,
+			status,
+			state.StartedAt.Local().Format(time.DateTime),
+			record.CommandLine(),
+		)
+	}
+
+	if !printed {
+		fmt.Println("no aliases running in background")
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(psCmd)
+
+	psCmd.Flags().BoolVarP(&psAll, "all", "a", false, "show finished runs too")
+	psCmd.Flags().BoolVar(&psPrune, "prune", false, "remove finished runs and their logs")
+}
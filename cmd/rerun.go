@@ -0,0 +1,88 @@
+/*
+Copyright © 2024 algrvvv <alexandrgr25@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algrvvv/ali/history"
+	"github.com/algrvvv/ali/utils"
+)
+
+var rerunCmd = &cobra.Command{
+	Use:     "rerun [id|last]",
+	Short:   "Run alias from history again",
+	Example: "ali rerun - repeat last run\nali rerun 42 - repeat run with id 42 from ali history",
+	Args:    cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		ref := "last"
+		if len(args) == 1 {
+			ref = args[0]
+		}
+
+		records, err := history.Load()
+		if err != nil {
+			utils.PrintError("failed to load history", err)
+			os.Exit(1)
+		}
+
+		record, err := history.FindRecord(records, ref)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		executable, err := os.Executable()
+		utils.CheckError(err)
+
+		fmt.Printf("rerun #%d: %s (in %s)\n", record.ID, record.CommandLine(), record.Dir)
+
+		// NOTE: запускаем ali заново с теми же аргументами в той же директории,
+		// так запуск повторяется в точности и тоже попадает в историю
+		cmd := exec.Command(executable, record.Argv...)
+		cmd.Dir = record.Dir
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+		// Ctrl+C обрабатывает запущенный ali.
+		// NOTE: не signal.Ignore, иначе игнорирование унаследуют и дочерние процессы
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt)
+
+		if err := cmd.Run(); err != nil {
+			code := utils.ExitCode(err)
+			if code < 0 {
+				utils.PrintError("failed to rerun", err)
+				code = 1
+			}
+			os.Exit(code)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rerunCmd)
+}
@@ -22,23 +22,33 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"slices"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/term"
 
-	"github.com/algrvvv/ali/local"
+	"github.com/algrvvv/ali/background"
+	"github.com/algrvvv/ali/history"
 	"github.com/algrvvv/ali/logger"
+	"github.com/algrvvv/ali/notify"
 	"github.com/algrvvv/ali/parallel"
+	"github.com/algrvvv/ali/sdk"
+	"github.com/algrvvv/ali/trust"
 	"github.com/algrvvv/ali/utils"
+	"github.com/algrvvv/ali/validate"
 )
 
-const localConfig = ".ali"
+const localConfig = sdk.LocalConfigName
 
 func init() {
 	home, err := os.UserHomeDir()
@@ -55,7 +65,20 @@ func init() {
 }
 
 var (
+	// aliConfig загруженная конфигурация
+	aliConfig *sdk.Config
+	// localViper ближайший локальный конфиг
 	localViper *viper.Viper
+	// localConfigFiles найденные локальные конфиги, ближайший - первый
+	localConfigFiles []string
+	// includedConfigFiles подключенные через include конфиги
+	includedConfigFiles []string
+	// includeErrors ошибки подключения конфигов
+	includeErrors []error
+	// trustStore база доверенных конфигов
+	trustStore *trust.Store
+	// globalConfigFile путь до глобального конфига, для истории запусков
+	globalConfigFile string
 
 	debug              bool
 	localEnv           bool
@@ -63,6 +86,13 @@ var (
 	withoutOutput      bool
 	outputColor        string
 	printResultCommand bool
+	parallelUI         string
+	outputMode         string
+	timestamps         string
+	logDir             string
+	detach             bool
+	strict             bool
+	profile            string
 
 	// rootCmd represents the base command when called without any subcommands
 	rootCmd = &cobra.Command{
@@ -76,69 +106,326 @@ var (
 				fmt.Println("use help for see usage")
 				return
 			}
-			alias := args[0]
-			params := args[1:]
+			// ali db migrate - алиас migrate группы db
+			alias, params := aliConfig.Lookup(args)
 			unknownFlags := parseUnknownFlags(os.Args[1:])
 
 			logger.SaveDebugf("got alias: %s", alias)
 			logger.SaveDebugf("got params(%d): %v", len(params), params)
 			logger.SaveDebugf("got unknown flags: %v", unknownFlags)
 
-			aliases := utils.LoadAliases(viper.GetViper())
-			aliasEntry := utils.SearchSynonyms(aliases, alias)
-			logger.SaveDebugf("got alias entry: %v", aliasEntry)
+			if strict {
+				aliConfig.Strict = true
+			}
 
-			if aliasEntry == nil {
-				fmt.Println("alias not found")
+			if detach && os.Getenv(background.EnvRunID) == "" {
+				startDetached(alias)
 				return
 			}
 
-			envs := utils.GetEnvs(aliasEntry)
+			record := &history.Record{
+				Alias:     alias,
+				Args:      params,
+				Flags:     unknownFlags,
+				Argv:      os.Args[1:],
+				StartedAt: time.Now(),
+			}
+			record.Dir, _ = os.Getwd()
 
-			if aliasEntry.Parallel {
-				parallel.ExecuteParallel(
-					aliasEntry,
-					params,
-					unknownFlags,
-					envs,
-					printResultCommand,
-				)
-			} else {
-				for _, command := range aliasEntry.Cmds {
-					err := local.ExecuteLocal(
-						command,
-						aliasEntry.Dir,
-						params,
-						unknownFlags,
-						envs,
-						printResultCommand,
-					)
-					if err != nil {
-						fmt.Println("failed to get cmd: ", err)
-						return
-					}
+			code, aliasEntry := runAlias(record, alias, params, unknownFlags)
+
+			record.Duration = time.Since(record.StartedAt)
+			record.ExitCode = code
+			if err := history.Save(record); err != nil {
+				logger.SaveDebugf("failed to save history record: %v", err)
+			}
+
+			if aliasEntry != nil {
+				notifyDone(aliasEntry, record)
+			}
+
+			// запуск в фоне: сохраняем результат для ali ps
+			if runID := os.Getenv(background.EnvRunID); runID != "" {
+				if err := background.Finish(runID, code); err != nil {
+					logger.SaveDebugf("failed to save background run result: %v", err)
+				}
+			}
+
+			if code != 0 {
+				if code < 0 {
+					code = 1
 				}
+				os.Exit(code)
 			}
 		},
 	}
 )
 
+// startDetached запускает алиас в фоне тем же набором аргументов, но без --detach.
+func startDetached(alias string) {
+	argv := make([]string, 0, len(os.Args)-1)
+	for _, arg := range os.Args[1:] {
+		if arg == "--detach" || arg == "-detach" || strings.HasPrefix(arg, "--detach=") {
+			continue
+		}
+		argv = append(argv, arg)
+	}
+
+	state, err := background.Start(alias, argv)
+	if err != nil {
+		utils.PrintError("failed to start alias in background", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("started %s%s%s in background: id %d, pid %d\n", color, alias, resetColor, state.ID, state.Pid)
+	fmt.Printf("use `ali logs -f %d` to see output and `ali stop %d` to stop it\n", state.ID, state.ID)
+}
+
+// runAlias выполняет алиас и возвращает код завершения и найденный алиас.
+func runAlias(
+	record *history.Record, alias string,
+	params []string, unknownFlags map[string]string,
+) (int, *utils.AliasEntry) {
+	// NOTE: алиас недоверенного конфига не запускаем, даже если есть одноименный доверенный
+	if file := untrustedSource(aliConfig, trustStore, alias); file != "" {
+		status := trustStore.Status(file)
+		fmt.Printf("alias %q is defined in %s config %s\n", alias, status, file)
+		if status == trust.StatusDenied {
+			fmt.Printf("use `ali allow %s` to trust it again\n", file)
+		} else {
+			fmt.Println("use `ali allow` to review its commands and trust it")
+		}
+		return 1, nil
+	}
+
+	aliasEntry, err := aliConfig.Alias(alias)
+	if err != nil {
+		logger.SaveDebugf("failed to get alias: %v", err)
+
+		// NOTE: для группы показываем ее алиасы
+		fmt.Println(err)
+		return 1, nil
+	}
+	logger.SaveDebugf("got alias entry: %v", aliasEntry)
+
+	record.Alias = aliasEntry.AliasName
+	record.Config = configSource(aliasEntry.AliasName)
+
+	if aliasEntry.Parallel {
+		commands, err := aliConfig.ParallelCommands(aliasEntry)
+		if err != nil {
+			utils.PrintError("failed to get parallel commands", err)
+			return 1, aliasEntry
+		}
+
+		inv, err := aliConfig.Invocation(aliasEntry, params, unknownFlags)
+		if err != nil {
+			utils.PrintError("failed to get vars", err)
+			return 1, aliasEntry
+		}
+
+		ui := aliasEntry.UI
+		if parallelUI != "" {
+			ui = parallelUI
+		}
+
+		mode := aliasEntry.OutputMode
+		if outputMode != "" {
+			mode = outputMode
+		}
+
+		stamps := aliasEntry.Timestamps
+		if timestamps != "" {
+			stamps = timestamps
+		}
+
+		logs := aliasEntry.LogDir
+		if logDir != "" {
+			logs = logDir
+		}
+
+		code := parallel.ExecuteParallel(
+			aliasEntry,
+			commands,
+			inv,
+			&parallel.Options{
+				PrintResultCommands: printResultCommand,
+				OutputColor:         outputColor,
+				WithoutOutput:       withoutOutput,
+				UI:                  ui,
+				TmuxLayout:          aliasEntry.TmuxLayout,
+				OutputMode:          mode,
+				Timestamps:          stamps,
+				LogDir:              logs,
+			},
+		)
+		return code, aliasEntry
+	}
+
+	plan, err := aliConfig.ResolveWith(aliasEntry.AliasName, params, unknownFlags)
+	if err != nil {
+		fmt.Println("failed to get cmd: ", err)
+		return 1, aliasEntry
+	}
+
+	runner := &sdk.Runner{
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+		// удаленной команде нужен терминал, только если он есть у самого ali
+		TTY: term.IsTerminal(int(os.Stdin.Fd())),
+	}
+	if printResultCommand {
+		runner.OnEvent = func(event sdk.Event) {
+			if event.Kind == sdk.EventStart {
+				fmt.Println("command: ", event.Step.Command)
+			}
+		}
+	}
+
+	// NOTE: Ctrl+C (и SIGTERM от ali stop) получает и сама команда,
+	// а ali дожидается ее завершения, чтобы записать результат в историю
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	code, err := runner.Run(context.Background(), plan)
+	if err != nil {
+		fmt.Println("failed to get cmd: ", err)
+	}
+
+	return code, aliasEntry
+}
+
+// notifyDone уведомляет о завершении алиаса, если это настроено в notify.
+func notifyDone(entry *utils.AliasEntry, record *history.Record) {
+	var global *notify.Config
+	if viper.IsSet("notify") {
+		global = &notify.Config{}
+		if err := viper.UnmarshalKey("notify", global); err != nil {
+			utils.PrintError("failed to get notify config", err)
+			return
+		}
+	}
+
+	cfg := notify.Merge(global, entry.Notify)
+	if cfg == nil {
+		return
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if !cfg.ShouldNotify(record.ExitCode, record.Duration) {
+		return
+	}
+
+	err := notify.Send(cfg, notify.Event{
+		Alias:    record.Alias,
+		ExitCode: record.ExitCode,
+		Duration: record.Duration,
+		Dir:      record.Dir,
+	})
+	if err != nil {
+		utils.PrintError("failed to send notification", err)
+	}
+}
+
+// configSource файл конфигурации, из которого взят алиас.
+func configSource(aliasName string) string {
+	for _, file := range localConfigFiles {
+		if definesAlias(file, aliasName) {
+			return file
+		}
+	}
+
+	return globalConfigFile
+}
+
+// untrustedSource пропущенный недоверенный конфиг, в котором описан алиас.
+// Запрещенные через ali deny конфиги не учитываются: одноименный доверенный алиас запускается.
+func untrustedSource(cfg *sdk.Config, store *trust.Store, aliasName string) string {
+	for _, file := range cfg.UntrustedFiles {
+		if store.Status(file) == trust.StatusDenied {
+			continue
+		}
+		if definesAlias(file, aliasName) {
+			return file
+		}
+	}
+
+	return ""
+}
+
+func definesAlias(file, aliasName string) bool {
+	v := viper.New()
+	utils.UseConfigFile(v, file)
+	if err := v.ReadInConfig(); err != nil {
+		return false
+	}
+
+	// алиас группы db:migrate описан в aliases.db.migrate
+	nested := strings.ReplaceAll(aliasName, utils.GroupSeparator, ".")
+	return v.IsSet("aliases."+aliasName) || v.IsSet("aliases."+nested) ||
+		v.IsSet(parallel.ParallelPrefix+"."+aliasName)
+}
+
+// getAliases дополнение имен алиасов по уровням: сначала алиасы и группы верхнего уровня,
+// после имени группы - ее алиасы. Имя с двоеточием (db:) дополняется полными именами.
 func getAliases(
 	cmd *cobra.Command,
 	args []string,
 	toComplete string,
 ) ([]string, cobra.ShellCompDirective) {
-	aliases, ok := viper.Get("aliases").(map[string]any)
-	if !ok {
-		fmt.Println("failed to get all aliases")
-		return []string{}, cobra.ShellCompDirectiveNoFileComp
+	aliases, err := aliConfig.Aliases()
+	if err != nil {
+		logger.SaveDebugf("failed to load some aliases: %v", err)
+	}
+	groups := aliConfig.Groups()
+
+	var level string
+	if len(args) > 0 {
+		name, rest := aliConfig.Lookup(args)
+		if _, ok := groups[name]; !ok || len(rest) > 0 {
+			// дальше идут аргументы алиаса
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		level = name + utils.GroupSeparator
 	}
+	full := len(args) == 0 && strings.Contains(toComplete, utils.GroupSeparator)
 
 	var res []string
-	for alias, command := range aliases {
-		res = append(res, fmt.Sprintf("%s%s%s\t%s", color, alias, resetColor, command))
+	add := func(name, desc string) {
+		if !strings.HasPrefix(name, level) {
+			return
+		}
+
+		short := strings.TrimPrefix(name, level)
+		if !full && strings.Contains(short, utils.GroupSeparator) {
+			return
+		}
+		res = append(res, fmt.Sprintf("%s\t%s", short, desc))
+	}
+
+	for name, entry := range aliases {
+		desc := entry.Desc
+		if desc == "" {
+			desc = strings.Join(entry.Cmds, "; ")
+		}
+		add(name, desc)
 	}
 
+	for name, group := range groups {
+		desc := "group"
+		if group.Desc != "" {
+			desc += ": " + group.Desc
+		}
+		add(name, desc)
+	}
+	slices.Sort(res)
+
 	return res, cobra.ShellCompDirectiveNoFileComp
 }
 
@@ -164,25 +451,112 @@ func init() {
 	// when this action is called directly.
 	rootCmd.PersistentFlags().BoolVarP(&debug, "debug", "D", false, "print debug messages")
 	rootCmd.PersistentFlags().BoolVarP(&localEnv, "local-env", "L", false, "use only local env")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "profile of vars and env (default $ALI_PROFILE or app.profile)")
 	rootCmd.PersistentFlags().BoolVarP(&doParallel, "parallel", "p", false, "do parallel command")
 	rootCmd.PersistentFlags().BoolVar(&withoutOutput, "without-output", false, "dont show parallel commands output")
 	rootCmd.PersistentFlags().StringVar(&outputColor, "output-color", "", "color of the ouput of the parallel command")
 	rootCmd.Flags().BoolVar(&printResultCommand, "print", false, "print result command before start exec")
+	rootCmd.Flags().StringVar(&parallelUI, "ui", "", "ui for parallel commands: stream, tui or tmux")
+	rootCmd.Flags().StringVar(&outputMode, "output-mode", "", "output of parallel commands: stream or grouped")
+	rootCmd.Flags().StringVar(&timestamps, "timestamps", "", "prefix parallel output with time: wall or relative")
+	rootCmd.Flags().StringVar(&logDir, "log-dir", "", "save parallel output to files in dir (true - ~/.ali/runs)")
+	rootCmd.Flags().BoolVar(&detach, "detach", false, "run alias in background (see ali ps)")
+	rootCmd.Flags().BoolVar(&strict, "strict", false, "don't run commands with unresolved {{var}}, <param> or $ENV")
 
 	// WARN: only for dev
 	// rootCmd.PersistentFlags().StringVar(&localConfig, "local-config", ".ali", "local config path")
 }
 
 func initConfig() {
-	if !localEnv {
-		initGlobalConfig()
+	trustStore = loadTrustStore()
+
+	cfg, err := sdk.Load(loadOptions())
+	if errors.Is(err, sdk.ErrProfileNotFound) {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	utils.CheckError(err)
+	aliConfig = cfg
+
+	globalConfigFile = cfg.GlobalFile
+	localConfigFiles = cfg.LocalFiles
+	includedConfigFiles = cfg.IncludedFiles
+	includeErrors = cfg.IncludeErrors
+	logger.SaveDebugf("using config: %s; local configs: %v", cfg.GlobalFile, cfg.LocalFiles)
+
+	for _, err := range includeErrors {
+		fmt.Println("failed to include config: ", err)
+		logger.SaveDebugf("failed to include config: %v", err)
+	}
+
+	// NOTE: остальные команды читают итоговую конфигурацию через глобальный viper
+	viper.SetConfigType(utils.YamlConfigurationType)
+	viper.AutomaticEnv()
+	if err := viper.MergeConfigMap(cfg.Settings()); err != nil {
+		utils.CheckError(err)
 	}
 
 	initLocalConfig()
-	initInclideConfigs()
+	validateOnLoad()
+	warnUntrusted()
+}
+
+// loadOptions настройки загрузки конфигурации по флагам запуска.
+func loadOptions() sdk.Options {
+	skip := skipGlobalConfig()
+	return sdk.Options{
+		NoGlobal:  localEnv || skip,
+		Profile:   profile,
+		NoProfile: skip,
+		Trusted:   trustStore.Trusted,
+	}
+}
+
+// loadTrustStore читает базу доверенных конфигов. Если ее не удалось прочитать,
+// не доверяем ни одному конфигу, а ali allow перезапишет базу.
+func loadTrustStore() *trust.Store {
+	path, err := trust.Path()
+	utils.CheckError(err)
+
+	store, err := trust.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read trust database %s: %v\n", path, err)
+		return trust.New(path)
+	}
+
+	return store
+}
+
+// warnUntrusted предупреждает о пропущенных недоверенных конфигах.
+// Запрещенные через ali deny конфиги пропускаются молча.
+func warnUntrusted() {
+	cmd, _, err := rootCmd.Find(os.Args[1:])
+	if err != nil {
+		return
+	}
+
+	// NOTE: команды ali trust сами показывают недоверенные конфиги
+	if cmd.Parent() == trustCmd {
+		return
+	}
+
+	switch cmd.Name() {
+	case "allow", "deny", "setup", "version", "help", "completion",
+		cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return
+	}
+
+	for _, file := range aliConfig.UntrustedFiles {
+		status := trustStore.Status(file)
+		logger.SaveDebugf("skipped %s config: %s", status, file)
+		if status != trust.StatusDenied {
+			fmt.Fprintf(os.Stderr, "skipped %s config: %s (use `ali allow` to review and trust it)\n", status, file)
+		}
+	}
 }
 
-func initGlobalConfig() {
+// skipGlobalConfig команды, которым не нужен глобальный конфиг.
+func skipGlobalConfig() bool {
 	cmd, _, err := rootCmd.Find(os.Args[1:])
 	if err != nil {
 		fmt.Println("error occurred: ", err)
@@ -190,99 +564,63 @@ func initGlobalConfig() {
 	}
 
 	// пропускаем если setup скип
-	if cmd.Name() == "setup" || cmd.Name() == "version" || cmd.Name() == "help" {
+	return cmd.Name() == "setup" || cmd.Name() == "version" || cmd.Name() == "help"
+}
+
+// validateOnLoad показывает ошибки конфигурации при каждом запуске,
+// чтобы опечатка в ключе не игнорировалась молча.
+func validateOnLoad() {
+	cmd, _, err := rootCmd.Find(os.Args[1:])
+	if err != nil {
 		return
 	}
 
-	home, err := os.UserHomeDir()
-	utils.CheckError(err)
-
-	path := filepath.Join(home, ".ali")
-	viper.AddConfigPath(path)
-	viper.SetConfigType(utils.YamlConfigurationType)
-	viper.SetConfigName("config")
+	// NOTE: вывод дополнения для shell нельзя ломать лишними строками
+	switch cmd.Name() {
+	case "validate", "setup", "version", "help", "completion",
+		cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return
+	}
 
-	viper.AutomaticEnv()
-	if err := viper.ReadInConfig(); err != nil {
-		utils.CheckError(err)
+	for _, issue := range validate.Files(loadedConfigFiles()) {
+		fmt.Fprintln(os.Stderr, issue)
+		logger.SaveDebugf("invalid config: %s", issue)
 	}
-	logger.SaveDebugf("using config: %s", viper.ConfigFileUsed())
 }
 
-func initLocalConfig() {
-	logger.SaveDebugf("local config: %s", localConfig)
+// loadedConfigFiles все загруженные конфиги в порядке загрузки.
+func loadedConfigFiles() []string {
+	return aliConfig.Files()
+}
 
+// initLocalConfig читает ближайший локальный конфиг отдельно, для шаблонов.
+func initLocalConfig() {
 	localViper = viper.New()
-	localViper.SetConfigName(localConfig)
-	localViper.SetConfigType(utils.YamlConfigurationType)
-	localViper.AddConfigPath(".")
 
-	viper.SetConfigName(localConfig)
-	viper.SetConfigType(utils.YamlConfigurationType)
-	viper.AddConfigPath(".")
+	if len(localConfigFiles) == 0 {
+		logger.SaveDebugf("local config not found")
+		return
+	}
 
+	utils.UseConfigFile(localViper, localConfigFiles[0])
 	if err := localViper.ReadInConfig(); err != nil {
 		logger.SaveDebugf("load local config error: %v", err)
-		// utils.CheckError(err)
 	} else {
 		logger.SaveDebugf("local viper read config successfully")
 	}
-
-	if localEnv {
-		viper.AutomaticEnv()
-		if err := viper.ReadInConfig(); err != nil {
-			logger.SaveDebugf("load local config error: %v", err)
-			// utils.CheckError(err)
-		}
-		logger.SaveDebugf("using config: %s", viper.ConfigFileUsed())
-	} else {
-		if err := viper.MergeInConfig(); err != nil {
-			logger.SaveDebugf("local config not found")
-		} else {
-			logger.SaveDebugf("local config loaded")
-		}
-	}
 }
 
-func initInclideConfigs() {
-	include := viper.GetStringSlice("include")
-	logger.SaveDebugf("includes: %v", include)
-
-	for _, includePath := range include {
-		if strings.HasPrefix(includePath, "~") {
-			logger.SaveDebugf("user use ~ in dir param")
-			home, err := os.UserHomeDir()
-			if err != nil {
-				logger.SaveDebugf("failed to get user home dir")
-				return
-			}
-
-			logger.SaveDebugf("got home user dir: %q", home)
-			includePath = strings.Replace(includePath, "~", home, 1)
-			logger.SaveDebugf("command dir after change ~ to home dir: %q", includePath)
-		}
-		logger.SaveDebugf("include path: %s", includePath)
-
-		info, err := os.Stat(includePath)
-		if err != nil {
-			fmt.Println("failed to get include file stat: ", err)
-			logger.SaveDebugf("failed to get include file stat: %v", err)
-			return
-		}
-
-		path := includePath
-		if info.IsDir() {
-			path = filepath.Join(path, ".ali")
-		}
-
-		logger.SaveDebugf("final unclude path: %s", path)
-		viper.SetConfigFile(path)
-		if err := viper.MergeInConfig(); err != nil {
-			logger.SaveDebugf("uncluded config not found")
-		} else {
-			logger.SaveDebugf("uncluded config loaded")
-		}
+// baseConfigFiles глобальный и локальные конфиги в порядке загрузки, без подключенных.
+func baseConfigFiles() []string {
+	var files []string
+	if globalConfigFile != "" {
+		files = append(files, globalConfigFile)
 	}
+	for i := len(localConfigFiles) - 1; i >= 0; i-- {
+		files = append(files, localConfigFiles[i])
+	}
+
+	return files
 }
 
 func initLogger() {
@@ -297,9 +635,16 @@ func initLogger() {
 func parseUnknownFlags(args []string) map[string]string {
 	reservedFlags := []string{
 		"-D", "-debug", "--debug",
-		"-print", "-print",
+		"-print", "--print",
 		"-L", "--local-config",
 		"-local-config",
+		"-ui", "--ui",
+		"-output-mode", "--output-mode",
+		"-timestamps", "--timestamps",
+		"-log-dir", "--log-dir",
+		"-detach", "--detach",
+		"-strict", "--strict",
+		"-profile", "--profile",
 	}
 
 	flags := make(map[string]string)
@@ -0,0 +1,68 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/algrvvv/ali/cmd"
+	"github.com/algrvvv/ali/sdk"
+	"github.com/algrvvv/ali/trust"
+)
+
+func TestUntrustedSource(t *testing.T) {
+	dir := t.TempDir()
+	global := filepath.Join(dir, "config.yml")
+	local := filepath.Join(dir, "project", ".ali")
+	if err := os.MkdirAll(filepath.Dir(local), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(global, []byte("aliases:\n  hello: echo global\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(local, []byte("root: true\naliases:\n  hello: echo local\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := trust.New(filepath.Join(dir, "trust.json"))
+	load := func() *sdk.Config {
+		cfg, err := sdk.Load(sdk.Options{
+			Dir:          filepath.Dir(local),
+			GlobalConfig: global,
+			NoProfile:    true,
+			Trusted:      store.Trusted,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cfg
+	}
+
+	// недоверенный конфиг перекрывает одноименный глобальный алиас
+	if got := cmd.UntrustedSource(load(), store, "hello"); got != local {
+		t.Errorf("ERROR: untrusted config: want: %s; got: %q", local, got)
+	} else {
+		t.Logf("SUCCESS! untrusted config: %s", got)
+	}
+
+	// запрещенный конфиг пропускается молча, запускается глобальный алиас
+	if err := store.Deny(local); err != nil {
+		t.Fatal(err)
+	}
+	cfg := load()
+	if got := cmd.UntrustedSource(cfg, store, "hello"); got != "" {
+		t.Errorf("ERROR: denied config: want: no untrusted source; got: %s", got)
+	} else {
+		t.Logf("SUCCESS! denied config is skipped")
+	}
+
+	alias, err := cfg.Alias("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alias.Cmds) != 1 || alias.Cmds[0] != "echo global" {
+		t.Errorf("ERROR: want: global alias; got: %v", alias.Cmds)
+	} else {
+		t.Logf("SUCCESS! got global alias: %v", alias.Cmds)
+	}
+}
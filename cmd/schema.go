@@ -0,0 +1,72 @@
+/*
+Copyright © 2024 algrvvv <alexandrgr25@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algrvvv/ali/utils"
+	"github.com/algrvvv/ali/validate"
+)
+
+var (
+	schemaOutput string
+
+	schemaCmd = &cobra.Command{
+		Use:   "schema",
+		Short: "Print JSON Schema of config files",
+		Long: `Print JSON Schema of .ali and config.yml for editors with YAML language server.
+Add to the top of the config:
+
+  # yaml-language-server: $schema=/path/to/ali.schema.json`,
+		Example: "ali schema -o ~/.ali/ali.schema.json",
+		Args:    cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			data, err := json.MarshalIndent(validate.Schema(), "", "  ")
+			if err != nil {
+				utils.PrintError("failed to build schema", err)
+				os.Exit(1)
+			}
+			data = append(data, '\n')
+
+			if schemaOutput == "" {
+				_, _ = os.Stdout.Write(data)
+				return
+			}
+
+			if err := os.WriteFile(schemaOutput, data, 0o644); err != nil {
+				utils.PrintError("failed to write schema", err)
+				os.Exit(1)
+			}
+			fmt.Println("schema saved to", schemaOutput)
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+	schemaCmd.Flags().StringVarP(&schemaOutput, "output", "o", "", "save schema to file")
+}
@@ -0,0 +1,193 @@
+/*
+Copyright © 2024 algrvvv <alexandrgr25@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algrvvv/ali/history"
+	"github.com/algrvvv/ali/logger"
+	"github.com/algrvvv/ali/utils"
+)
+
+var (
+	statsSince      string
+	statsDir        string
+	statsLimit      int
+	statsJSON       bool
+	statsPrometheus string
+
+	statsCmd = &cobra.Command{
+		Use:   "stats",
+		Short: "Show usage statistics of aliases",
+		Example: "ali stats - most used aliases, durations and failures\n" +
+			"ali stats --since 30d --dir . - statistics of current project for the last month\n" +
+			"ali stats --prometheus /var/lib/node_exporter/ali.prom - export for node_exporter",
+		Args: cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			var filter history.Filter
+			if statsDir != "" {
+				dir, err := filepath.Abs(statsDir)
+				utils.CheckError(err)
+				filter.Dir = dir
+			}
+
+			if statsSince != "" {
+				since, err := history.ParseSince(statsSince, time.Now())
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				filter.Since = since
+			}
+
+			records, err := history.Load()
+			if err != nil {
+				utils.PrintError("failed to load history", err)
+				os.Exit(1)
+			}
+
+			stats := history.ComputeStats(history.FilterRecords(records, filter), knownAliases())
+
+			if statsPrometheus != "" {
+				if err := history.WritePrometheus(statsPrometheus, stats); err != nil {
+					utils.PrintError("failed to write prometheus metrics", err)
+					os.Exit(1)
+				}
+				return
+			}
+
+			if statsJSON {
+				data, err := json.MarshalIndent(stats, "", "  ")
+				utils.CheckError(err)
+				fmt.Println(string(data))
+				return
+			}
+
+			printStats(stats)
+		},
+	}
+)
+
+// knownAliases все алиасы текущей конфигурации, включая описанные только в секции parallel.
+func knownAliases() []string {
+	var known []string
+	aliases, err := aliConfig.Aliases()
+	if err != nil {
+		logger.SaveDebugf("failed to load some aliases: %v", err)
+	}
+
+	for name := range aliases {
+		known = append(known, name)
+	}
+
+	return known
+}
+
+func printStats(stats history.Stats) {
+	if stats.Runs == 0 {
+		fmt.Println("history is empty")
+	} else {
+		fmt.Printf("Runs: %d, failed: %d (%.0f%%)\n\n",
+			stats.Runs, stats.Failures, 100*float64(stats.Failures)/float64(stats.Runs))
+
+		fmt.Println("Most used aliases:")
+		printAliasStats(stats.Aliases)
+
+		fmt.Println()
+		fmt.Println("By project:")
+		home, _ := os.UserHomeDir()
+		for _, project := range stats.Projects {
+			dir := project.Project
+			if home != "" && strings.HasPrefix(dir, home) {
+				dir = "~" + strings.TrimPrefix(dir, home)
+			}
+
+			top := make([]string, 0, 3)
+			for _, as := range project.Aliases[:min(3, len(project.Aliases))] {
+				top = append(top, fmt.Sprintf("%s (%d)", as.Alias, as.Runs))
+			}
+
+			fmt.Printf("  %s%s%s - runs: %d, failed: %d; %s\n",
+				color, dir, resetColor, project.Runs, project.Failures, strings.Join(top, ", "))
+		}
+	}
+
+	if len(stats.NeverUsed) > 0 {
+		fmt.Println()
+		fmt.Println("Never used aliases:")
+		for _, alias := range stats.NeverUsed {
+			fmt.Printf("  - %s\n", alias)
+		}
+	}
+}
+
+func printAliasStats(aliases []history.AliasStats) {
+	fmt.Printf("  %-20s %-6s %-8s %-10s %-10s %-7s %s\n", "ALIAS", "RUNS", "FAILED", "AVG", "P95", "TREND", "LAST RUN")
+
+	for i, as := range aliases {
+		if statsLimit > 0 && i >= statsLimit {
+			fmt.Printf("  ... and %d more\n", len(aliases)-statsLimit)
+			break
+		}
+
+		failed := fmt.Sprintf("%-8s", fmt.Sprintf("%.0f%%", 100*as.FailureRate))
+		if as.Failures > 0 {
+			failed = utils.Colorize(failed, "red")
+		}
+
+		trend := fmt.Sprintf("%-7s", "-")
+		if as.Trend != 0 {
+			trend = fmt.Sprintf("%-7s", fmt.Sprintf("%+.0f%%", 100*as.Trend))
+			if as.Trend > 0.2 {
+				trend = utils.Colorize(trend, "yellow")
+			}
+		}
+
+		fmt.Printf("  %s%-20s%s %-6d %s %-10s %-10s %s %s\n",
+			color, utils.TruncateString(as.Alias, 20), resetColor,
+			as.Runs,
+			failed,
+			as.AvgDuration.Round(time.Millisecond),
+			as.P95Duration.Round(time.Millisecond),
+			trend,
+			as.LastRun.Local().Format(time.DateTime),
+		)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().StringVarP(&statsSince, "since", "s", "", "count runs since duration (24h, 7d) or date (2006-01-02)")
+	statsCmd.Flags().StringVarP(&statsDir, "dir", "d", "", "count only runs in dir and its subdirs")
+	statsCmd.Flags().IntVarP(&statsLimit, "limit", "n", 10, "count of aliases to show (0 - all)")
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "print statistics as json")
+	statsCmd.Flags().StringVar(&statsPrometheus, "prometheus", "", "write statistics to file for node_exporter textfile collector")
+}
@@ -0,0 +1,64 @@
+/*
+Copyright © 2024 algrvvv <alexandrgr25@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algrvvv/ali/background"
+	"github.com/algrvvv/ali/utils"
+)
+
+var (
+	stopTimeout time.Duration
+
+	stopCmd = &cobra.Command{
+		Use:     "stop <alias|id>",
+		Short:   "Stop alias running in background",
+		Example: "ali stop dev - stop last run of dev\nali stop 3 - stop run with id 3 from ali ps",
+		Args:    cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			state, err := background.Find(args[0])
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			if err := background.Stop(state, stopTimeout); err != nil {
+				utils.PrintError(fmt.Sprintf("failed to stop run %d", state.ID), err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("stopped %s%s%s (id %d)\n", color, state.Alias, resetColor, state.ID)
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(stopCmd)
+
+	stopCmd.Flags().DurationVarP(&stopTimeout, "timeout", "t", 5*time.Second, "time to wait before killing processes")
+}
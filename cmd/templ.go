@@ -119,9 +119,8 @@ func useTempl(templName string) error {
 		return err
 	}
 
-	localConfigFile := filepath.Join(wordDir, ".ali")
-	_, err = os.Stat(localConfigFile)
-	if err == nil {
+	localConfigFile := filepath.Join(wordDir, localConfig)
+	if _, ok := utils.FindConfigIn(wordDir, localConfig); ok {
 		if !forceAction {
 			fmt.Println("local config file already exists")
 			fmt.Println("use --force for force rewrite")
@@ -144,6 +143,9 @@ func useTempl(templName string) error {
 		if err := localViper.WriteConfig(); err != nil {
 			return err
 		}
+		if err := trustStore.Allow(localViper.ConfigFileUsed()); err != nil {
+			return err
+		}
 
 		fmt.Println("local config rewrited")
 		return nil
@@ -168,6 +170,11 @@ func useTempl(templName string) error {
 		return err
 	}
 
+	// NOTE: конфиг создан из своего шаблона, ему можно доверять
+	if err := trustStore.Allow(localConfigFile); err != nil {
+		return err
+	}
+
 	fmt.Println("local config from template created")
 	return nil
 }
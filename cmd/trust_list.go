@@ -0,0 +1,65 @@
+/*
+Copyright © 2024 algrvvv <alexandrgr25@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algrvvv/ali/trust"
+)
+
+var trustListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show allowed, denied and skipped configs",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		entries := trustStore.Entries()
+
+		// NOTE: найденные, но еще не разрешенные конфиги в базе не хранятся
+		for _, file := range aliConfig.UntrustedFiles {
+			entry, ok := trustStore.Entry(file)
+			if !ok && !slices.ContainsFunc(entries, func(e trust.Entry) bool { return e.Path == entry.Path }) {
+				entries = append(entries, entry)
+			}
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("no trusted configs")
+			return
+		}
+
+		for _, entry := range entries {
+			when := "-"
+			if !entry.Time.IsZero() {
+				when = entry.Time.Format("2006-01-02 15:04")
+			}
+			fmt.Printf("%-10s %-16s %s\n", trustStore.Status(entry.Path), when, entry.Path)
+		}
+	},
+}
+
+func init() {
+	trustCmd.AddCommand(trustListCmd)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright © 2024 algrvvv <alexandrgr25@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var whichConfigCmd = &cobra.Command{
+	Use:   "which-config",
+	Short: "Show config files in load order",
+	Long: `Show config files in load order: later ones override earlier ones.
+Local .ali files are searched in the current and parent directories up to
+the repository root, the filesystem root or a config with "root: true".`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if !localEnv && globalConfigFile != "" {
+			fmt.Printf("global   %s\n", globalConfigFile)
+		}
+
+		// ближайший конфиг загружается последним
+		for i := len(localConfigFiles) - 1; i >= 0; i-- {
+			fmt.Printf("local    %s\n", localConfigFiles[i])
+		}
+
+		for _, file := range includedConfigFiles {
+			fmt.Printf("include  %s\n", file)
+		}
+
+		for _, file := range aliConfig.UntrustedFiles {
+			fmt.Printf("skipped  %s (%s)\n", file, trustStore.Status(file))
+		}
+
+		if len(localConfigFiles) == 0 && len(aliConfig.UntrustedFiles) == 0 {
+			fmt.Println("local config not found")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whichConfigCmd)
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package filelock
+
+import (
+	"os"
+	"syscall"
+)
+
+// Lock берет эксклюзивную блокировку файла, ждет, пока ее не отпустит другой процесс.
+func Lock(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+}
+
+// Unlock снимает блокировку, взятую Lock.
+func Unlock(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package filelock
+
+import (
+	"math"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// Lock берет эксклюзивную блокировку файла, ждет, пока ее не отпустит другой процесс.
+func Lock(file *os.File) error {
+	return windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0,
+		math.MaxUint32, math.MaxUint32, new(windows.Overlapped))
+}
+
+// Unlock снимает блокировку, взятую Lock.
+func Unlock(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, math.MaxUint32, math.MaxUint32, new(windows.Overlapped))
+}
@@ -0,0 +1,56 @@
+// Package filelock блокирует файлы ali, которые одновременно меняют разные запуски:
+// историю, базу доверенных конфигов, состояние расписания.
+package filelock
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// LockPath берет блокировку на файле path, создавая его, и возвращает функцию,
+// которая ее снимает. Для файлов, которые заменяются через переименование,
+// блокируется отдельный файл рядом с ними: path + ".lock".
+func LockPath(path string) (unlock func(), err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Lock(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return func() {
+		_ = Unlock(file)
+		file.Close()
+	}, nil
+}
+
+// WriteFile атомарно заменяет файл path: пишет во временный файл в той же
+// директории и переименовывает его, так что читатели не видят половину файла.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
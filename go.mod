@@ -3,6 +3,8 @@ module github.com/algrvvv/ali
 go 1.23.3
 
 require (
+	github.com/creack/pty v1.1.21
+	github.com/fsnotify/fsnotify v1.7.0
 	github.com/lmittmann/tint v1.0.5
 	github.com/mdobak/go-xerrors v0.3.1
 	github.com/mitchellh/mapstructure v1.5.0
@@ -10,11 +12,13 @@ require (
 	github.com/samber/slog-multi v1.2.4
 	github.com/spf13/cobra v1.8.1
 	github.com/spf13/viper v1.19.0
+	golang.org/x/crypto v0.21.0
+	golang.org/x/sys v0.28.0
+	golang.org/x/term v0.27.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/fsnotify/fsnotify v1.7.0 // indirect
 	github.com/hashicorp/hcl v1.0.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/magiconair/properties v1.8.7 // indirect
@@ -29,7 +33,6 @@ require (
 	go.uber.org/atomic v1.9.0 // indirect
 	go.uber.org/multierr v1.9.0 // indirect
 	golang.org/x/exp v0.0.0-20230905200255-921286631fa9 // indirect
-	golang.org/x/sys v0.18.0 // indirect
 	golang.org/x/text v0.18.0 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
 )
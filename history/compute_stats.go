@@ -0,0 +1,177 @@
+package history
+
+import (
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+// AliasStats статистика запусков одного алиаса.
+type AliasStats struct {
+	Alias       string        `json:"alias"`
+	Runs        int           `json:"runs"`
+	Failures    int           `json:"failures"`
+	FailureRate float64       `json:"failure_rate"`
+	AvgDuration time.Duration `json:"avg_duration"`
+	P95Duration time.Duration `json:"p95_duration"`
+	// Trend насколько последние запуски медленнее (> 0) или быстрее (< 0) предыдущих
+	Trend   float64   `json:"trend"`
+	LastRun time.Time `json:"last_run"`
+}
+
+// ProjectStats статистика запусков в одном проекте.
+type ProjectStats struct {
+	Project  string       `json:"project"`
+	Runs     int          `json:"runs"`
+	Failures int          `json:"failures"`
+	Aliases  []AliasStats `json:"aliases"`
+}
+
+// Stats сводка по истории запусков.
+type Stats struct {
+	Runs     int            `json:"runs"`
+	Failures int            `json:"failures"`
+	Aliases  []AliasStats   `json:"aliases"`
+	Projects []ProjectStats `json:"projects"`
+	// NeverUsed алиасы из конфигурации, которые ни разу не запускались
+	NeverUsed []string `json:"never_used"`
+}
+
+// Project проект, в котором был запуск: директория локального конфига,
+// если алиас взят из него, иначе директория запуска.
+func (r *Record) Project() string {
+	// NOTE: локальный конфиг может быть в любом формате: .ali, .ali.toml, .ali.json...
+	if r.Config != "" && slices.Contains(utils.ConfigFileNames(".ali"), filepath.Base(r.Config)) {
+		return filepath.Dir(r.Config)
+	}
+
+	return r.Dir
+}
+
+// ComputeStats считает статистику по записям истории.
+// known - все алиасы текущей конфигурации, для поиска неиспользуемых.
+func ComputeStats(records []Record, known []string) Stats {
+	stats := Stats{
+		Aliases:   aliasStats(records),
+		Projects:  []ProjectStats{},
+		NeverUsed: []string{},
+	}
+
+	byProject := make(map[string][]Record)
+	used := make(map[string]bool)
+	for _, record := range records {
+		stats.Runs++
+		if record.Failed() {
+			stats.Failures++
+		}
+
+		used[record.Alias] = true
+		byProject[record.Project()] = append(byProject[record.Project()], record)
+	}
+
+	for project, projectRecords := range byProject {
+		ps := ProjectStats{Project: project, Aliases: aliasStats(projectRecords)}
+		for _, record := range projectRecords {
+			ps.Runs++
+			if record.Failed() {
+				ps.Failures++
+			}
+		}
+		stats.Projects = append(stats.Projects, ps)
+	}
+
+	sort.Slice(stats.Projects, func(i, j int) bool {
+		if stats.Projects[i].Runs != stats.Projects[j].Runs {
+			return stats.Projects[i].Runs > stats.Projects[j].Runs
+		}
+		return stats.Projects[i].Project < stats.Projects[j].Project
+	})
+
+	for _, alias := range known {
+		if !used[alias] {
+			stats.NeverUsed = append(stats.NeverUsed, alias)
+		}
+	}
+	slices.Sort(stats.NeverUsed)
+
+	return stats
+}
+
+// aliasStats статистика по алиасам, самые используемые - первые.
+func aliasStats(records []Record) []AliasStats {
+	durations := make(map[string][]time.Duration)
+	byAlias := make(map[string]*AliasStats)
+	for _, record := range records {
+		as, ok := byAlias[record.Alias]
+		if !ok {
+			as = &AliasStats{Alias: record.Alias}
+			byAlias[record.Alias] = as
+		}
+
+		as.Runs++
+		if record.Failed() {
+			as.Failures++
+		}
+		if record.StartedAt.After(as.LastRun) {
+			as.LastRun = record.StartedAt
+		}
+		durations[record.Alias] = append(durations[record.Alias], record.Duration)
+	}
+
+	out := make([]AliasStats, 0, len(byAlias))
+	for alias, as := range byAlias {
+		as.FailureRate = float64(as.Failures) / float64(as.Runs)
+		as.Trend = trend(durations[alias])
+		as.AvgDuration, as.P95Duration = avgAndP95(durations[alias])
+		out = append(out, *as)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Runs != out[j].Runs {
+			return out[i].Runs > out[j].Runs
+		}
+		return out[i].Alias < out[j].Alias
+	})
+
+	return out
+}
+
+// минимум запусков, чтобы сравнивать последние запуски с предыдущими
+const minTrendRuns = 4
+
+// trend сравнивает среднюю длительность второй половины запусков с первой.
+// durations должны идти в порядке запусков.
+func trend(durations []time.Duration) float64 {
+	if len(durations) < minTrendRuns {
+		return 0
+	}
+
+	half := len(durations) / 2
+	before, _ := avgAndP95(slices.Clone(durations[:half]))
+	after, _ := avgAndP95(slices.Clone(durations[len(durations)-half:]))
+	if before == 0 {
+		return 0
+	}
+
+	return float64(after)/float64(before) - 1
+}
+
+func avgAndP95(durations []time.Duration) (time.Duration, time.Duration) {
+	if len(durations) == 0 {
+		return 0, 0
+	}
+
+	slices.Sort(durations)
+
+	var sum time.Duration
+	for _, d := range durations {
+		sum += d
+	}
+
+	// перцентиль методом ближайшего ранга
+	rank := (95*len(durations) + 99) / 100
+	return sum / time.Duration(len(durations)), durations[rank-1]
+}
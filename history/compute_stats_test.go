@@ -0,0 +1,82 @@
+package history_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/algrvvv/ali/history"
+)
+
+func TestComputeStats(t *testing.T) {
+	var records []history.Record
+	for i := 1; i <= 20; i++ {
+		records = append(records, history.Record{
+			Alias:    "build",
+			Dir:      "/home/user/app/api",
+			Config:   "/home/user/app/.ali",
+			Duration: time.Duration(i) * time.Second,
+		})
+	}
+	records = append(records,
+		history.Record{Alias: "test", Dir: "/tmp", ExitCode: 1, Duration: time.Second},
+		history.Record{Alias: "test", Dir: "/tmp", Duration: time.Second},
+	)
+
+	stats := history.ComputeStats(records, []string{"build", "test", "deploy", "clean"})
+
+	if stats.Runs != 22 || stats.Failures != 1 {
+		t.Errorf("ERROR: want: 22 runs, 1 failure; got: %d runs, %d failures", stats.Runs, stats.Failures)
+	}
+
+	build := stats.Aliases[0]
+	if build.Alias != "build" || build.AvgDuration != 10500*time.Millisecond || build.P95Duration != 19*time.Second {
+		t.Errorf("ERROR: want: build avg 10.5s p95 19s; got: %s avg %s p95 %s", build.Alias, build.AvgDuration, build.P95Duration)
+	} else {
+		t.Logf("SUCCESS! got: %s avg %s p95 %s", build.Alias, build.AvgDuration, build.P95Duration)
+	}
+
+	if build.Trend <= 0 {
+		t.Errorf("ERROR: want: build slowing down; got trend: %f", build.Trend)
+	}
+
+	if test := stats.Aliases[1]; test.FailureRate != 0.5 {
+		t.Errorf("ERROR: want: test failure rate 0.5; got: %f", test.FailureRate)
+	}
+
+	if !slices.Equal(stats.NeverUsed, []string{"clean", "deploy"}) {
+		t.Errorf("ERROR: want: never used [clean deploy]; got: %v", stats.NeverUsed)
+	}
+
+	if len(stats.Projects) != 2 || stats.Projects[0].Project != "/home/user/app" || stats.Projects[1].Project != "/tmp" {
+		t.Errorf("ERROR: want: projects /home/user/app and /tmp; got: %+v", stats.Projects)
+	} else {
+		t.Logf("SUCCESS! got projects: %s, %s", stats.Projects[0].Project, stats.Projects[1].Project)
+	}
+}
+
+func TestRecordProject(t *testing.T) {
+	tests := []struct {
+		config   string
+		expected string
+	}{
+		{config: "/home/user/app/.ali", expected: "/home/user/app"},
+		{config: "/home/user/app/.ali.yml", expected: "/home/user/app"},
+		{config: "/home/user/app/.ali.yaml", expected: "/home/user/app"},
+		{config: "/home/user/app/.ali.toml", expected: "/home/user/app"},
+		{config: "/home/user/app/.ali.json", expected: "/home/user/app"},
+		// глобальный и подключенные конфиги - не проект
+		{config: "/home/user/.ali/config.yml", expected: "/home/user/app/api"},
+		{config: "/home/user/shared/aliases.toml", expected: "/home/user/app/api"},
+		{expected: "/home/user/app/api"},
+	}
+
+	for _, test := range tests {
+		record := history.Record{Dir: "/home/user/app/api", Config: test.config}
+		if got := record.Project(); got != test.expected {
+			t.Errorf("ERROR: %s: want: %s; got: %s", test.config, test.expected, got)
+		} else {
+			t.Logf("SUCCESS! %s: got: %s", test.config, got)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package history
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter условия выборки записей истории. Пустые поля не учитываются.
+type Filter struct {
+	Alias  string
+	Failed bool
+	// Dir директория запуска; подходят и запуски во вложенных директориях
+	Dir   string
+	Since time.Time
+	// Limit сколько последних записей оставить
+	Limit int
+}
+
+// FilterRecords возвращает записи, подходящие под фильтр, от старых к новым.
+func FilterRecords(records []Record, filter Filter) []Record {
+	var out []Record
+	for _, record := range records {
+		if filter.Alias != "" && record.Alias != filter.Alias {
+			continue
+		}
+
+		if filter.Failed && !record.Failed() {
+			continue
+		}
+
+		if filter.Dir != "" && !inDir(record.Dir, filter.Dir) {
+			continue
+		}
+
+		if !filter.Since.IsZero() && record.StartedAt.Before(filter.Since) {
+			continue
+		}
+
+		out = append(out, record)
+	}
+
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[len(out)-filter.Limit:]
+	}
+
+	return out
+}
+
+func inDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// FindRecord ищет запись по id или последнюю запись для ref = "last".
+func FindRecord(records []Record, ref string) (*Record, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("history is empty")
+	}
+
+	if ref == "" || ref == "last" {
+		return &records[len(records)-1], nil
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(ref, "#"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid history id: %q", ref)
+	}
+
+	for i := range records {
+		if records[i].ID == id {
+			return &records[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("history record #%d not found", id)
+}
+
+// ParseSince разбирает значение --since: длительность (30m, 24h, 7d)
+// или дату (2006-01-02, 2006-01-02T15:04:05Z07:00).
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+
+	if t, err := time.ParseInLocation(time.DateOnly, value, now.Location()); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid since value: %q (use duration like 24h, 7d or date 2006-01-02)", value)
+}
@@ -0,0 +1,84 @@
+package history_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/algrvvv/ali/history"
+)
+
+func TestFilterRecords(t *testing.T) {
+	now := time.Date(2024, 10, 10, 12, 0, 0, 0, time.UTC)
+	records := []history.Record{
+		{ID: 1, Alias: "build", Dir: "/home/user/app", StartedAt: now.Add(-48 * time.Hour)},
+		{ID: 2, Alias: "test", Dir: "/home/user/app/api", StartedAt: now.Add(-2 * time.Hour), ExitCode: 1},
+		{ID: 3, Alias: "build", Dir: "/home/user/other", StartedAt: now.Add(-time.Hour)},
+		{ID: 4, Alias: "build", Dir: "/home/user/app", StartedAt: now, ExitCode: 2},
+	}
+
+	tests := []struct {
+		name     string
+		filter   history.Filter
+		expected []int
+	}{
+		{name: "all", filter: history.Filter{}, expected: []int{1, 2, 3, 4}},
+		{name: "alias", filter: history.Filter{Alias: "build"}, expected: []int{1, 3, 4}},
+		{name: "failed", filter: history.Filter{Failed: true}, expected: []int{2, 4}},
+		{name: "dir", filter: history.Filter{Dir: "/home/user/app"}, expected: []int{1, 2, 4}},
+		{name: "since", filter: history.Filter{Since: now.Add(-3 * time.Hour)}, expected: []int{2, 3, 4}},
+		{name: "limit", filter: history.Filter{Alias: "build", Limit: 2}, expected: []int{3, 4}},
+	}
+
+	for _, test := range tests {
+		got := history.FilterRecords(records, test.filter)
+
+		ids := make([]int, 0, len(got))
+		for _, record := range got {
+			ids = append(ids, record.ID)
+		}
+
+		if len(ids) != len(test.expected) {
+			t.Errorf("ERROR: %s: want: %v; got: %v", test.name, test.expected, ids)
+			continue
+		}
+
+		for i := range ids {
+			if ids[i] != test.expected[i] {
+				t.Errorf("ERROR: %s: want: %v; got: %v", test.name, test.expected, ids)
+				break
+			}
+		}
+		t.Logf("SUCCESS! %s: got: %v", test.name, ids)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 10, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+		err      bool
+	}{
+		{input: "2h", expected: now.Add(-2 * time.Hour)},
+		{input: "7d", expected: now.AddDate(0, 0, -7)},
+		{input: "2024-10-01", expected: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{input: "yesterday", err: true},
+	}
+
+	for _, test := range tests {
+		got, err := history.ParseSince(test.input, now)
+		if test.err {
+			if err == nil {
+				t.Errorf("ERROR: %s: want error; got: %s", test.input, got)
+			}
+			continue
+		}
+
+		if err != nil || !got.Equal(test.expected) {
+			t.Errorf("ERROR: %s: want: %s; got: %s (%v)", test.input, test.expected, got, err)
+		} else {
+			t.Logf("SUCCESS! %s: got: %s", test.input, got)
+		}
+	}
+}
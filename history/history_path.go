@@ -0,0 +1,19 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const fileName = "history.jsonl"
+
+// Path путь до файла истории: ~/.ali/history.jsonl.
+// Каждая строка файла - одна запись в формате json.
+func Path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".ali", fileName), nil
+}
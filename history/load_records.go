@@ -0,0 +1,55 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/algrvvv/ali/logger"
+)
+
+// Load читает всю историю запусков, от старых записей к новым.
+// Если истории еще нет - возвращается пустой список.
+func Load() ([]Record, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return readRecords(file)
+}
+
+func readRecords(r io.Reader) ([]Record, error) {
+	var records []Record
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var record Record
+			if jsonErr := json.Unmarshal(line, &record); jsonErr != nil {
+				// NOTE: битую строку (например, недописанную) просто пропускаем
+				logger.SaveDebugf("skip invalid history record: %v", jsonErr)
+			} else {
+				records = append(records, record)
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+}
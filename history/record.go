@@ -0,0 +1,41 @@
+package history
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record один запуск алиаса через ali.
+type Record struct {
+	ID    int               `json:"id"`
+	Alias string            `json:"alias"`
+	Args  []string          `json:"args,omitempty"`
+	Flags map[string]string `json:"flags,omitempty"`
+	// Argv аргументы ali целиком (без имени программы), по ним выполняется rerun
+	Argv []string `json:"argv"`
+	Dir  string   `json:"dir"`
+	// Config файл конфигурации, в котором найден алиас
+	Config    string        `json:"config,omitempty"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	ExitCode  int           `json:"exit_code"`
+}
+
+func (r *Record) Failed() bool {
+	return r.ExitCode != 0
+}
+
+// CommandLine команда, которой был выполнен запуск.
+func (r *Record) CommandLine() string {
+	parts := make([]string, 0, len(r.Argv)+1)
+	parts = append(parts, "ali")
+	for _, arg := range r.Argv {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'$`\\") {
+			arg = strconv.Quote(arg)
+		}
+		parts = append(parts, arg)
+	}
+
+	return strings.Join(parts, " ")
+}
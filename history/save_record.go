@@ -0,0 +1,71 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/algrvvv/ali/filelock"
+)
+
+// размер хвоста файла, в котором ищется последняя запись для нового id
+const tailSize = 64 * 1024
+
+// Save дописывает запись в конец истории, выдавая ей следующий id.
+func Save(record *Record) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// NOTE: несколько ali могут завершиться одновременно,
+	// без блокировки они выдадут записям одинаковый id
+	if err := filelock.Lock(file); err != nil {
+		return err
+	}
+	defer filelock.Unlock(file)
+
+	record.ID = lastID(file) + 1
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// lastID id последней записи в истории, читается только конец файла.
+func lastID(file *os.File) int {
+	info, err := file.Stat()
+	if err != nil {
+		return 0
+	}
+
+	offset := max(info.Size()-tailSize, 0)
+	tail := bufio.NewReader(io.NewSectionReader(file, offset, info.Size()-offset))
+	if offset > 0 {
+		// первая строка хвоста, скорее всего, обрезана
+		_, _ = tail.ReadBytes('\n')
+	}
+
+	records, err := readRecords(tail)
+	if err != nil || len(records) == 0 {
+		return 0
+	}
+
+	return records[len(records)-1].ID
+}
@@ -0,0 +1,52 @@
+package history_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/algrvvv/ali/history"
+)
+
+func TestSaveConcurrent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", t.TempDir())
+
+	const writers = 100
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := history.Save(&history.Record{Alias: "build"}); err != nil {
+				t.Errorf("ERROR: save: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	records, err := history.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != writers {
+		t.Fatalf("ERROR: want: %d records; got: %d", writers, len(records))
+	}
+
+	seen := make(map[int]bool)
+	for _, record := range records {
+		if seen[record.ID] {
+			t.Errorf("ERROR: duplicate id %d", record.ID)
+		}
+		seen[record.ID] = true
+	}
+
+	for id := 1; id <= writers; id++ {
+		if !seen[id] {
+			t.Errorf("ERROR: missing id %d", id)
+		}
+	}
+
+	t.Logf("SUCCESS! %d writers got unique ids", writers)
+}
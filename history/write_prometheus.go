@@ -0,0 +1,99 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WritePrometheus записывает статистику в формате textfile-коллектора node_exporter.
+// Файл сначала пишется рядом и потом переименовывается, чтобы коллектор
+// не прочитал его наполовину записанным.
+func WritePrometheus(path string, stats Stats) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".ali-stats-*.prom")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := writeMetrics(tmp, stats); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+type metric struct {
+	name  string
+	help  string
+	kind  string
+	value func(AliasStats) float64
+}
+
+var metrics = []metric{
+	{
+		name:  "ali_alias_runs_total",
+		help:  "Number of alias runs.",
+		kind:  "counter",
+		value: func(s AliasStats) float64 { return float64(s.Runs) },
+	},
+	{
+		name:  "ali_alias_failures_total",
+		help:  "Number of alias runs with non-zero exit code.",
+		kind:  "counter",
+		value: func(s AliasStats) float64 { return float64(s.Failures) },
+	},
+	{
+		name:  "ali_alias_duration_avg_seconds",
+		help:  "Average duration of alias runs.",
+		kind:  "gauge",
+		value: func(s AliasStats) float64 { return s.AvgDuration.Seconds() },
+	},
+	{
+		name:  "ali_alias_duration_p95_seconds",
+		help:  "95th percentile of alias run duration.",
+		kind:  "gauge",
+		value: func(s AliasStats) float64 { return s.P95Duration.Seconds() },
+	},
+	{
+		name:  "ali_alias_last_run_timestamp_seconds",
+		help:  "Unix time of the last alias run.",
+		kind:  "gauge",
+		value: func(s AliasStats) float64 { return float64(s.LastRun.Unix()) },
+	},
+}
+
+func writeMetrics(w io.Writer, stats Stats) error {
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind); err != nil {
+			return err
+		}
+
+		for _, project := range stats.Projects {
+			for _, as := range project.Aliases {
+				_, err := fmt.Fprintf(w, "%s{alias=\"%s\",project=\"%s\"} %g\n",
+					m.name, escapeLabel(as.Alias), escapeLabel(project.Project), m.value(as))
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
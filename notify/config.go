@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// события, о которых можно уведомлять
+const (
+	OnSuccess = "success"
+	OnFailure = "failure"
+)
+
+// варианты escape-последовательности уведомления терминала
+const (
+	EscapeOSC9   = "osc9"
+	EscapeOSC777 = "osc777"
+	EscapeNone   = "none"
+)
+
+// Config настройки уведомлений о завершении алиаса.
+// Задается глобально (`notify:`) и у отдельного алиаса.
+type Config struct {
+	// After уведомлять только о запусках, которые шли дольше.
+	// Указатель, чтобы алиас мог переопределить глобальное значение нулем
+	After *time.Duration `mapstructure:"after"`
+	// On при каком результате уведомлять: success, failure (по умолчанию - оба)
+	On []string `mapstructure:"on"`
+	// Escape escape-последовательность для терминала: osc9 (по умолчанию), osc777 или none
+	Escape string `mapstructure:"escape"`
+	// Bell дополнительно звенеть в терминал
+	Bell bool `mapstructure:"bell"`
+	// Command команда-уведомитель, получает ALI_ALIAS, ALI_EXIT_CODE, ALI_DURATION и т.д.
+	Command string `mapstructure:"command"`
+}
+
+// Merge накладывает настройки алиаса на глобальные: заданные поля алиаса важнее.
+func Merge(global, alias *Config) *Config {
+	switch {
+	case global == nil:
+		return alias
+	case alias == nil:
+		return global
+	}
+
+	merged := *global
+	if alias.After != nil {
+		merged.After = alias.After
+	}
+	if len(alias.On) > 0 {
+		merged.On = alias.On
+	}
+	if alias.Escape != "" {
+		merged.Escape = alias.Escape
+	}
+	if alias.Bell {
+		merged.Bell = true
+	}
+	if alias.Command != "" {
+		merged.Command = alias.Command
+	}
+
+	return &merged
+}
+
+// Validate проверяет значения on и escape.
+func (c *Config) Validate() error {
+	for _, on := range c.On {
+		if on != OnSuccess && on != OnFailure {
+			return fmt.Errorf("unsupported notify.on value: %q (use: %s, %s)", on, OnSuccess, OnFailure)
+		}
+	}
+
+	switch c.Escape {
+	case "", EscapeOSC9, EscapeOSC777, EscapeNone:
+	default:
+		return fmt.Errorf("unsupported notify.escape value: %q (use: %s, %s, %s)", c.Escape, EscapeOSC9, EscapeOSC777, EscapeNone)
+	}
+
+	return nil
+}
+
+// ShouldNotify нужно ли уведомлять о запуске с таким результатом и длительностью.
+func (c *Config) ShouldNotify(code int, duration time.Duration) bool {
+	if c.After != nil && duration < *c.After {
+		return false
+	}
+
+	if len(c.On) == 0 {
+		return true
+	}
+
+	if code == 0 {
+		return slices.Contains(c.On, OnSuccess)
+	}
+	return slices.Contains(c.On, OnFailure)
+}
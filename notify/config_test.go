@@ -0,0 +1,39 @@
+package notify_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/algrvvv/ali/notify"
+)
+
+func TestShouldNotify(t *testing.T) {
+	after := 30 * time.Second
+	zero := time.Duration(0)
+
+	global := &notify.Config{After: &after}
+	alias := &notify.Config{After: &zero, On: []string{notify.OnFailure}}
+
+	tests := []struct {
+		name     string
+		cfg      *notify.Config
+		code     int
+		duration time.Duration
+		expected bool
+	}{
+		{name: "global short run", cfg: global, duration: time.Second, expected: false},
+		{name: "global long run", cfg: global, duration: time.Minute, expected: true},
+		{name: "global long failed run", cfg: global, code: 1, duration: time.Minute, expected: true},
+		{name: "alias short failed run", cfg: notify.Merge(global, alias), code: 2, duration: time.Second, expected: true},
+		{name: "alias successful run", cfg: notify.Merge(global, alias), duration: time.Minute, expected: false},
+	}
+
+	for _, test := range tests {
+		got := test.cfg.ShouldNotify(test.code, test.duration)
+		if got != test.expected {
+			t.Errorf("ERROR: %s: want: %v; got: %v", test.name, test.expected, got)
+		} else {
+			t.Logf("SUCCESS! %s: got: %v", test.name, got)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Event завершение алиаса, о котором уведомляем.
+type Event struct {
+	Alias    string
+	ExitCode int
+	Duration time.Duration
+	Dir      string
+}
+
+func (e Event) status() string {
+	if e.ExitCode == 0 {
+		return OnSuccess
+	}
+	return OnFailure
+}
+
+func (e Event) message() string {
+	duration := e.Duration.Round(time.Second)
+	if e.Duration < time.Second {
+		duration = e.Duration.Round(time.Millisecond)
+	}
+
+	if e.ExitCode == 0 {
+		return fmt.Sprintf("%s finished in %s", e.Alias, duration)
+	}
+	return fmt.Sprintf("%s failed with exit code %d after %s", e.Alias, e.ExitCode, duration)
+}
+
+// Send отправляет уведомление всеми настроенными способами.
+// Escape-последовательности и звонок пишутся, только если stderr - терминал.
+func Send(cfg *Config, event Event) error {
+	if term.IsTerminal(int(os.Stderr.Fd())) {
+		var out strings.Builder
+		switch cfg.Escape {
+		case "", EscapeOSC9:
+			out.WriteString(wrapTmux(fmt.Sprintf("\x1b]9;ali: %s\x07", event.message())))
+		case EscapeOSC777:
+			out.WriteString(wrapTmux(fmt.Sprintf("\x1b]777;notify;ali;%s\x07", event.message())))
+		}
+
+		if cfg.Bell {
+			out.WriteString("\a")
+		}
+
+		fmt.Fprint(os.Stderr, out.String())
+	}
+
+	if cfg.Command == "" {
+		return nil
+	}
+
+	return runCommand(cfg.Command, event)
+}
+
+// wrapTmux внутри tmux escape-последовательность нужно передать терминалу через passthrough.
+func wrapTmux(seq string) string {
+	if os.Getenv("TMUX") == "" {
+		return seq
+	}
+
+	return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+}
+
+func runCommand(command string, event Event) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("cmd.exe", "/C", command)
+	case "linux", "darwin":
+		cmd = exec.Command("sh", "-c", command)
+	default:
+		return errors.New("unsupported OS")
+	}
+
+	cmd.Dir = event.Dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"ALI_ALIAS="+event.Alias,
+		"ALI_EXIT_CODE="+strconv.Itoa(event.ExitCode),
+		"ALI_STATUS="+event.status(),
+		"ALI_DURATION="+event.Duration.Round(time.Millisecond).String(),
+		"ALI_DURATION_SECONDS="+strconv.FormatFloat(event.Duration.Seconds(), 'f', 3, 64),
+		"ALI_DIR="+event.Dir,
+		"ALI_MESSAGE="+event.message(),
+	)
+
+	return cmd.Run()
+}
@@ -0,0 +1,60 @@
+package parallel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CheckOrder проверяет, что метки команд уникальны, start_after ссылается
+// на существующие команды и зависимости не образуют цикл.
+func CheckOrder(commands []Command) error {
+	byLabel := make(map[string]Command, len(commands))
+	for _, c := range commands {
+		if _, ok := byLabel[c.Label]; ok {
+			return fmt.Errorf("duplicate command label: %q", c.Label)
+		}
+		byLabel[c.Label] = c
+	}
+
+	for _, c := range commands {
+		for _, dep := range c.StartAfter {
+			if _, ok := byLabel[dep]; !ok {
+				return fmt.Errorf("[%s] start_after unknown command: %q", c.Label, dep)
+			}
+		}
+	}
+
+	// 0 - не посещена, 1 - в обходе, 2 - проверена
+	state := make(map[string]int, len(commands))
+	var path []string
+
+	var visit func(label string) error
+	visit = func(label string) error {
+		switch state[label] {
+		case 1:
+			return fmt.Errorf("start_after cycle: %s -> %s", strings.Join(path, " -> "), label)
+		case 2:
+			return nil
+		}
+
+		state[label] = 1
+		path = append(path, label)
+		for _, dep := range byLabel[label].StartAfter {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[label] = 2
+
+		return nil
+	}
+
+	for _, c := range commands {
+		if err := visit(c.Label); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
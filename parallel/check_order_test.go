@@ -0,0 +1,57 @@
+package parallel_test
+
+import (
+	"testing"
+
+	"github.com/algrvvv/ali/parallel"
+)
+
+func TestCheckOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []parallel.Command
+		wantErr  bool
+	}{
+		{
+			name: "valid order",
+			commands: []parallel.Command{
+				{Label: "db"},
+				{Label: "api", StartAfter: []string{"db"}},
+				{Label: "web", StartAfter: []string{"api", "db"}},
+			},
+		},
+		{
+			name: "unknown dependency",
+			commands: []parallel.Command{
+				{Label: "api", StartAfter: []string{"db"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate label",
+			commands: []parallel.Command{
+				{Label: "db"},
+				{Label: "db"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cycle",
+			commands: []parallel.Command{
+				{Label: "a", StartAfter: []string{"c"}},
+				{Label: "b", StartAfter: []string{"a"}},
+				{Label: "c", StartAfter: []string{"b"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		err := parallel.CheckOrder(test.commands)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ERROR: %s: want error: %v; got: %v", test.name, test.wantErr, err)
+		} else {
+			t.Logf("SUCCESS! %s: got: %v", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,557 @@
+package parallel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+// сколько последних строк вывода хранится для каждой команды
+const maxLogLines = 5000
+
+var ansiRe = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+var statusColors = map[Status]string{
+	StatusWaiting:    "gray",
+	StatusRunning:    "green",
+	StatusReady:      "green",
+	StatusRestarting: "yellow",
+	StatusExited:     "gray",
+	StatusFailed:     "red",
+	StatusStopped:    "yellow",
+	StatusNotStarted: "red",
+}
+
+// dashboard полноэкранный интерфейс для параллельных алиасов:
+// список команд с их состоянием и лог выбранной команды.
+type dashboard struct {
+	alias string
+	procs []*Process
+	dirty atomic.Bool
+
+	mu        sync.Mutex
+	logs      map[*Process][]string
+	selected  int
+	focused   bool
+	scroll    int // сколько последних строк пропущено; 0 - следим за новыми строками
+	page      int
+	searching bool
+	input     string
+	query     string
+	match     int
+	message   string
+}
+
+func newDashboard(alias string) *dashboard {
+	return &dashboard{
+		alias: alias,
+		logs:  make(map[*Process][]string),
+		match: -1,
+	}
+}
+
+// canUseDashboard проверяет, что ввод и вывод - это терминал.
+func canUseDashboard() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+func (d *dashboard) Line(proc *Process, line string, _ bool) {
+	d.appendLog(proc, sanitizeLine(line))
+}
+
+func (d *dashboard) Event(proc *Process, msg string) {
+	d.appendLog(proc, utils.Colorize("── "+msg, "gray"))
+
+	d.mu.Lock()
+	d.message = fmt.Sprintf("[%s] %s", proc.command.Label, msg)
+	d.mu.Unlock()
+}
+
+func (d *dashboard) Finished(*Process, int, time.Duration) {}
+
+func (d *dashboard) appendLog(proc *Process, line string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	lines := append(d.logs[proc], line)
+	if len(lines) > maxLogLines {
+		lines = lines[len(lines)-maxLogLines:]
+		if d.match >= 0 && d.current() == proc {
+			d.match--
+		}
+	}
+	d.logs[proc] = lines
+
+	// если пользователь листает лог, не сдвигаем то, что он читает
+	if d.scroll > 0 && d.current() == proc {
+		d.scroll++
+	}
+
+	d.dirty.Store(true)
+}
+
+// current выбранная команда, nil если команд нет.
+func (d *dashboard) current() *Process {
+	if d.selected < 0 || d.selected >= len(d.procs) {
+		return nil
+	}
+
+	return d.procs[d.selected]
+}
+
+func (d *dashboard) run(ctx context.Context) error {
+	in := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(in, state)
+
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer fmt.Print("\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan string, 64)
+	go readKeys(os.Stdin, keys)
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	lastRender := time.Time{}
+	for {
+		// перерисовываем при изменениях, но не чаще тика; раз в секунду - ради uptime
+		if d.dirty.Swap(false) || time.Since(lastRender) >= time.Second {
+			d.render()
+			lastRender = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case key, ok := <-keys:
+			if !ok || d.handleKey(key) {
+				return nil
+			}
+			d.dirty.Store(true)
+		case <-ticker.C:
+		}
+	}
+}
+
+// handleKey обрабатывает нажатие, возвращает true для выхода.
+func (d *dashboard) handleKey(key string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.searching {
+		switch key {
+		case "ctrl+c":
+			return true
+		case "esc":
+			d.searching = false
+		case "enter":
+			d.searching = false
+			d.query = d.input
+			d.match = -1
+			if d.query != "" {
+				lines := d.logs[d.current()]
+				d.findMatch(len(lines)-1-d.scroll, -1)
+			}
+		case "backspace":
+			if d.input != "" {
+				_, size := utf8.DecodeLastRuneInString(d.input)
+				d.input = d.input[:len(d.input)-size]
+			}
+		default:
+			if utf8.RuneCountInString(key) == 1 {
+				d.input += key
+			}
+		}
+		return false
+	}
+
+	proc := d.current()
+	if proc == nil {
+		return key == "q" || key == "ctrl+c"
+	}
+
+	switch key {
+	case "q", "ctrl+c":
+		return true
+	case "up", "k":
+		d.selectProcess(d.selected - 1)
+	case "down", "j":
+		d.selectProcess(d.selected + 1)
+	case "enter", "f":
+		d.focused = !d.focused
+	case "r":
+		d.message = fmt.Sprintf("[%s] restart requested", proc.command.Label)
+		go proc.Restart()
+	case "s":
+		d.message = fmt.Sprintf("[%s] stop requested", proc.command.Label)
+		go proc.Stop()
+	case "/":
+		d.searching = true
+		d.input = ""
+	case "n":
+		if d.query != "" {
+			from := d.match - 1
+			if d.match < 0 {
+				from = len(d.logs[proc]) - 1 - d.scroll
+			}
+			d.findMatch(from, -1)
+		}
+	case "N":
+		if d.query != "" && d.match >= 0 {
+			d.findMatch(d.match+1, 1)
+		}
+	case "esc":
+		d.query = ""
+		d.match = -1
+		d.focused = false
+	case "pgup", "ctrl+u":
+		d.scrollBy(d.page)
+	case "pgdn", "ctrl+d":
+		d.scrollBy(-d.page)
+	case "home", "g":
+		d.scrollBy(len(d.logs[proc]))
+	case "end", "G":
+		d.scroll = 0
+	}
+
+	return false
+}
+
+func (d *dashboard) selectProcess(idx int) {
+	if idx < 0 || idx >= len(d.procs) {
+		return
+	}
+
+	d.selected = idx
+	d.scroll = 0
+	d.match = -1
+}
+
+func (d *dashboard) scrollBy(n int) {
+	total := len(d.logs[d.current()])
+	d.scroll = max(0, min(d.scroll+n, total-d.page))
+}
+
+// findMatch ищет строку с query, начиная с from, в направлении dir (-1 - к старым строкам).
+func (d *dashboard) findMatch(from, dir int) {
+	lines := d.logs[d.current()]
+	query := strings.ToLower(d.query)
+
+	for i := from; i >= 0 && i < len(lines); i += dir {
+		if strings.Contains(strings.ToLower(stripANSI(lines[i])), query) {
+			d.match = i
+			// показываем совпадение примерно посередине лога
+			d.scroll = 0
+			d.scrollBy(len(lines) - i - d.page/2 - 1)
+			return
+		}
+	}
+
+	d.message = fmt.Sprintf("no more matches for %q", d.query)
+}
+
+func (d *dashboard) render() {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = 80, 24
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	proc := d.current()
+	var rows []string
+
+	title := fmt.Sprintf(" ali dashboard: %s (%d commands)", d.alias, len(d.procs))
+	if d.message != "" {
+		title += "   " + utils.Colorize(d.message, "gray")
+	}
+	rows = append(rows, title)
+
+	if !d.focused {
+		rows = append(rows, fmt.Sprintf("   %-20s %-12s %-10s %-9s %s", "LABEL", "STATUS", "UPTIME", "RESTARTS", "EXIT"))
+		for i, p := range d.procs {
+			rows = append(rows, processRow(p.State(), i == d.selected))
+		}
+	}
+
+	separator := "── logs "
+	if proc != nil {
+		separator = "── logs: " + proc.command.Label + " "
+	}
+	if d.query != "" {
+		separator += fmt.Sprintf("── search: %q ", d.query)
+	}
+	if d.scroll > 0 {
+		separator += fmt.Sprintf("── scrolled up %d lines ", d.scroll)
+	}
+	separator += strings.Repeat("─", max(0, width-utf8.RuneCountInString(separator)))
+	rows = append(rows, separator)
+
+	// оставляем одну строку под подсказку
+	d.page = max(1, height-len(rows)-1)
+	lines := d.logs[proc]
+	end := max(0, len(lines)-d.scroll)
+	start := max(0, end-d.page)
+
+	for i := start; i < end; i++ {
+		line := lines[i]
+		if d.query != "" {
+			line = highlight(line, d.query, i == d.match)
+		}
+		rows = append(rows, line)
+	}
+	for len(rows) < height-1 {
+		rows = append(rows, "")
+	}
+
+	if d.searching {
+		rows = append(rows, "/"+d.input+"\x1b[7m \x1b[0m")
+	} else {
+		rows = append(rows, utils.Colorize(
+			" ↑/↓ select  enter focus  r restart  s stop  / search  n/N older/newer  PgUp/PgDn scroll  q quit",
+			"gray",
+		))
+	}
+
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, row := range rows[:min(len(rows), height)] {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(fitLine(row, width))
+		b.WriteString("\x1b[K")
+	}
+	b.WriteString("\x1b[J")
+
+	_, _ = os.Stdout.WriteString(b.String())
+}
+
+func processRow(state State, selected bool) string {
+	marker := "  "
+	if selected {
+		marker = "> "
+	}
+
+	uptime := "-"
+	if state.Status == StatusRunning || state.Status == StatusReady {
+		uptime = time.Since(state.StartedAt).Round(time.Second).String()
+	}
+
+	exitCode := "-"
+	if state.Exited {
+		exitCode = strconv.Itoa(state.ExitCode)
+	}
+
+	return fmt.Sprintf(" %s%s %s %-10s %-9d %s",
+		marker,
+		utils.Colorize(fmt.Sprintf("%-20s", utils.TruncateString(state.Label, 20)), state.Color),
+		utils.Colorize(fmt.Sprintf("%-12s", state.Status), statusColors[state.Status]),
+		uptime,
+		state.Restarts,
+		exitCode,
+	)
+}
+
+// sanitizeLine готовит строку вывода к показу: от перерисовок через \r оставляем
+// только последнее состояние строки, а табы заменяем на пробелы.
+func sanitizeLine(line string) string {
+	line = strings.TrimRight(line, "\r")
+	if idx := strings.LastIndex(line, "\r"); idx >= 0 {
+		line = line[idx+1:]
+	}
+
+	return strings.ReplaceAll(line, "\t", "    ")
+}
+
+func stripANSI(s string) string {
+	return ansiRe.ReplaceAllString(s, "")
+}
+
+// highlight подсвечивает вхождения query в строке (без учета регистра).
+func highlight(line, query string, current bool) string {
+	plain := stripANSI(line)
+	lower, lowerQuery := strings.ToLower(plain), strings.ToLower(query)
+	if len(lower) != len(plain) || !strings.Contains(lower, lowerQuery) {
+		return line
+	}
+
+	style := "\x1b[30;43m"
+	if current {
+		style = "\x1b[30;46m"
+	}
+
+	var b strings.Builder
+	for {
+		idx := strings.Index(lower, lowerQuery)
+		if idx < 0 {
+			b.WriteString(plain)
+			break
+		}
+
+		b.WriteString(plain[:idx])
+		b.WriteString(style + plain[idx:idx+len(query)] + "\x1b[0m")
+		plain, lower = plain[idx+len(query):], lower[idx+len(query):]
+	}
+
+	return b.String()
+}
+
+// fitLine обрезает строку до ширины терминала, не считая escape-последовательности.
+func fitLine(s string, width int) string {
+	var b strings.Builder
+	visible := 0
+
+	for i := 0; i < len(s); {
+		if s[i] == 0x1b {
+			j := i + 1
+			if j < len(s) && s[j] == '[' {
+				j++
+				for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+					j++
+				}
+				if j < len(s) {
+					j++
+				}
+				b.WriteString(s[i:j])
+			} else {
+				// остальные последовательности (OSC и т.д.) просто пропускаем
+				j++
+			}
+			i = j
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if r < 0x20 || visible >= width {
+			continue
+		}
+
+		b.WriteRune(r)
+		visible++
+	}
+
+	b.WriteString("\x1b[0m")
+	return b.String()
+}
+
+// readKeys читает нажатия клавиш из терминала в raw режиме.
+func readKeys(r io.Reader, keys chan<- string) {
+	defer close(keys)
+
+	buf := make([]byte, 256)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			return
+		}
+
+		for _, key := range parseKeys(buf[:n]) {
+			keys <- key
+		}
+	}
+}
+
+var escapeKeys = map[string]string{
+	"\x1b[A":  "up",
+	"\x1b[B":  "down",
+	"\x1bOA":  "up",
+	"\x1bOB":  "down",
+	"\x1b[5~": "pgup",
+	"\x1b[6~": "pgdn",
+	"\x1b[H":  "home",
+	"\x1b[F":  "end",
+	"\x1b[1~": "home",
+	"\x1b[4~": "end",
+}
+
+func parseKeys(buf []byte) []string {
+	var keys []string
+
+	for len(buf) > 0 {
+		switch buf[0] {
+		case 0x1b:
+			if len(buf) == 1 {
+				keys = append(keys, "esc")
+				buf = buf[1:]
+				continue
+			}
+
+			found := false
+			for seq, key := range escapeKeys {
+				if strings.HasPrefix(string(buf), seq) {
+					keys = append(keys, key)
+					buf = buf[len(seq):]
+					found = true
+					break
+				}
+			}
+			if !found {
+				// неизвестная последовательность - пропускаем ее целиком
+				keys = append(keys, "esc")
+				buf = skipEscape(buf)
+			}
+		case 0x03:
+			keys = append(keys, "ctrl+c")
+			buf = buf[1:]
+		case 0x04:
+			keys = append(keys, "ctrl+d")
+			buf = buf[1:]
+		case 0x15:
+			keys = append(keys, "ctrl+u")
+			buf = buf[1:]
+		case '\r', '\n':
+			keys = append(keys, "enter")
+			buf = buf[1:]
+		case 0x7f, 0x08:
+			keys = append(keys, "backspace")
+			buf = buf[1:]
+		default:
+			r, size := utf8.DecodeRune(buf)
+			keys = append(keys, string(r))
+			buf = buf[size:]
+		}
+	}
+
+	return keys
+}
+
+// skipEscape пропускает escape-последовательность в начале buf.
+func skipEscape(buf []byte) []byte {
+	if len(buf) < 2 {
+		return buf[len(buf):]
+	}
+
+	switch buf[1] {
+	case '[':
+		// CSI: параметры и промежуточные байты, затем финальный байт 0x40-0x7e
+		i := 2
+		for i < len(buf) && (buf[i] < 0x40 || buf[i] > 0x7e) {
+			i++
+		}
+		return buf[min(i+1, len(buf)):]
+	case 'O':
+		return buf[min(3, len(buf)):]
+	default:
+		// alt+клавиша: ESC отдельно, сама клавиша обработается дальше
+		return buf[1:]
+	}
+}
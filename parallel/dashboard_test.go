@@ -0,0 +1,154 @@
+package parallel_test
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"testing"
+
+	"github.com/algrvvv/ali/parallel"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"q", []string{"q"}},
+		{"jk", []string{"j", "k"}},
+		{"\x1b[A\x1b[B", []string{"up", "down"}},
+		{"\x1bOA", []string{"up"}},
+		{"\x1b[5~\x1b[6~", []string{"pgup", "pgdn"}},
+		{"\x1b", []string{"esc"}},
+		{"\x1b[1;5C", []string{"esc"}},
+		{"\x03\x04\x15", []string{"ctrl+c", "ctrl+d", "ctrl+u"}},
+		{"\r\x7f", []string{"enter", "backspace"}},
+		{"ё/", []string{"ё", "/"}},
+	}
+
+	for _, test := range tests {
+		if got := parallel.ParseKeys([]byte(test.input)); !slices.Equal(got, test.want) {
+			t.Errorf("ERROR: %q: want: %v; got: %v", test.input, test.want, got)
+		} else {
+			t.Logf("SUCCESS! %q: %v", test.input, got)
+		}
+	}
+}
+
+func TestFitLine(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		want  string
+	}{
+		{"hello", 10, "hello\x1b[0m"},
+		{"hello world", 5, "hello\x1b[0m"},
+		{"\x1b[32mgreen\x1b[0m text", 3, "\x1b[32mgre\x1b[0m\x1b[0m"},
+		{"привет", 3, "при\x1b[0m"},
+		{"a\x07b", 5, "ab\x1b[0m"},
+	}
+
+	for _, test := range tests {
+		if got := parallel.FitLine(test.input, test.width); got != test.want {
+			t.Errorf("ERROR: %q (%d): want: %q; got: %q", test.input, test.width, test.want, got)
+		} else {
+			t.Logf("SUCCESS! %q (%d): %q", test.input, test.width, got)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		line    string
+		query   string
+		current bool
+		want    string
+	}{
+		{"no match", "xyz", false, "no match"},
+		{"Error: error", "error", false, "\x1b[30;43mError\x1b[0m: \x1b[30;43merror\x1b[0m"},
+		{"\x1b[31mfail\x1b[0m", "fail", true, "\x1b[30;46mfail\x1b[0m"},
+	}
+
+	for _, test := range tests {
+		if got := parallel.Highlight(test.line, test.query, test.current); got != test.want {
+			t.Errorf("ERROR: %q: want: %q; got: %q", test.line, test.want, got)
+		} else {
+			t.Logf("SUCCESS! %q: %q", test.line, got)
+		}
+	}
+}
+
+func TestDashboardSelection(t *testing.T) {
+	dash := parallel.NewDashboard("dev")
+
+	var procs []*parallel.Process
+	for i := range 3 {
+		command := parallel.Command{Label: fmt.Sprintf("cmd%d", i)}
+		procs = append(procs, parallel.NewProcess(command, exec.Command("true"), nil))
+	}
+	parallel.SetDashboardProcs(dash, procs)
+
+	keys := []struct {
+		key      string
+		selected int
+	}{
+		{"up", 0},
+		{"down", 1},
+		{"j", 2},
+		{"j", 2},
+		{"k", 1},
+	}
+	for _, k := range keys {
+		parallel.DashboardHandleKey(dash, k.key)
+		if selected, _, _, _ := parallel.DashboardState(dash); selected != k.selected {
+			t.Errorf("ERROR: after %q: want: %d; got: %d", k.key, k.selected, selected)
+		}
+	}
+
+	if parallel.DashboardCurrent(dash) != procs[1] {
+		t.Errorf("ERROR: current command is not the selected one")
+	}
+
+	for i := range 10 {
+		parallel.DashboardLine(dash, procs[1], fmt.Sprintf("line %d", i))
+	}
+	parallel.DashboardLine(dash, procs[0], "line of another command")
+
+	for _, key := range parallel.ParseKeys([]byte("/line 3\r")) {
+		parallel.DashboardHandleKey(dash, key)
+	}
+	if _, _, match, _ := parallel.DashboardState(dash); match != 3 {
+		t.Errorf("ERROR: want: match on line 3; got: %d", match)
+	} else {
+		t.Logf("SUCCESS! match: %d", match)
+	}
+
+	// смена команды сбрасывает поиск и прокрутку
+	parallel.DashboardHandleKey(dash, "up")
+	if selected, scroll, match, _ := parallel.DashboardState(dash); selected != 0 || scroll != 0 || match != -1 {
+		t.Errorf("ERROR: want: selected 0, scroll 0, match -1; got: %d, %d, %d", selected, scroll, match)
+	}
+
+	if !parallel.DashboardHandleKey(dash, "q") {
+		t.Errorf("ERROR: q must quit")
+	}
+}
+
+func TestDashboardEmpty(t *testing.T) {
+	dash := parallel.NewDashboard("empty")
+
+	if parallel.DashboardCurrent(dash) != nil {
+		t.Errorf("ERROR: want: no current command")
+	}
+
+	for _, key := range []string{"up", "down", "r", "s", "enter", "n", "g"} {
+		if parallel.DashboardHandleKey(dash, key) {
+			t.Errorf("ERROR: %q must not quit", key)
+		}
+	}
+	if !parallel.DashboardHandleKey(dash, "ctrl+c") {
+		t.Errorf("ERROR: ctrl+c must quit")
+	} else {
+		t.Logf("SUCCESS! empty dashboard handles keys")
+	}
+}
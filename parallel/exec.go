@@ -1,85 +1,265 @@
 package parallel
 
 import (
-	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
-	"runtime"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/algrvvv/ali/logger"
 	"github.com/algrvvv/ali/utils"
 )
 
-func Exec(command Command, outputColor string, withoutOutput bool, wg *sync.WaitGroup) {
+func Exec(ctx context.Context, proc *Process, deps []*Process, wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	commandLabel := utils.Colorize(command.Label, command.Color)
-	label := utils.Colorize(fmt.Sprintf("[%s]", command.Label), command.Color)
+	command := proc.command
 
-	vars, err := utils.GetVars()
-	if err != nil {
-		logger.SaveDebugf("failed to get all vars: %v", err)
-		fmt.Println("failed to get vars. skip")
-	} else {
-		logger.SaveDebugf("got vars: %v", vars)
-		command.Command = utils.GetVariables(command.Command, vars)
+	// ждем, пока все команды из start_after станут готовы
+	if !waitDeps(ctx, proc, deps) {
+		if !proc.keepAlive || !proc.waitRestart(ctx) {
+			return
+		}
 	}
 
-	fmt.Printf("Running command: %s\n", commandLabel)
-	logger.SaveDebugf("result command: %s", command.Command)
+	proc.event("running: %s", command.Command)
+
+	// проверка готовности живет, пока команда (с учетом перезапусков) не завершится окончательно
+	probeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	probeOnce := sync.Once{}
+	cmd := proc.cmd
+
+	var autoRestarts int
+	for {
+		startedAt := time.Now()
+		err := run(proc, cmd, func() {
+			probeOnce.Do(func() { go watchReady(probeCtx, proc) })
+		})
+		code := utils.ExitCode(err)
+		proc.out.Finished(proc, code, time.Since(startedAt))
+		proc.finished(code)
+
+		var restart bool
+		switch proc.takeAction() {
+		case actionRestart:
+			proc.event("restarting")
+			restart = true
+		case actionStop:
+			proc.setStatus(StatusStopped)
+			proc.event("stopped")
+		default:
+			if ctx.Err() != nil || !command.shouldRestart(code) {
+				break
+			}
+
+			if command.MaxRestarts > 0 && autoRestarts >= command.MaxRestarts {
+				proc.event("exited with code %d; max restarts (%d) reached", code, command.MaxRestarts)
+				break
+			}
+
+			autoRestarts++
+			delay := command.backoff(autoRestarts)
+			proc.setStatus(StatusRestarting)
+			proc.event("exited with code %d; restarting in %s (%s)", code, delay, restartsInfo(autoRestarts, command.MaxRestarts))
+
+			restart = proc.sleep(ctx, delay)
+		}
 
-	var cmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		cmd = exec.Command("cmd", "/C", fmt.Sprintf("cd %s && %s", command.Path, command.Command))
-	} else {
-		cmd = exec.Command("sh", "-c", fmt.Sprintf("cd %s && %s", command.Path, command.Command))
+		if !restart {
+			proc.settle(false, "exited before becoming ready")
+			proc.event("%s exited.", command.Command)
+
+			if ctx.Err() != nil || !proc.keepAlive || !proc.waitRestart(ctx) {
+				break
+			}
+		}
+
+		proc.mu.Lock()
+		proc.restarts++
+		proc.mu.Unlock()
+
+		// exec.Cmd нельзя запустить повторно, поэтому создаем новый с теми же параметрами
+		cmd = &exec.Cmd{Path: cmd.Path, Args: cmd.Args, Env: cmd.Env, Dir: cmd.Dir, SysProcAttr: cmd.SysProcAttr}
 	}
+}
 
-	// елси нет четко задонного цвета логов, пытаемся сохранить исходный.
-	if outputColor == "" {
-		cmd.Env = append(os.Environ(), "FORCE_COLOR=1")
+func restartsInfo(restarts, maxRestarts int) string {
+	if maxRestarts > 0 {
+		return fmt.Sprintf("restart %d/%d", restarts, maxRestarts)
 	}
+	return fmt.Sprintf("restart %d", restarts)
+}
 
-	stdout, _ := cmd.StdoutPipe()
-	stderr, _ := cmd.StderrPipe()
+func waitDeps(ctx context.Context, proc *Process, deps []*Process) bool {
+	for _, dep := range deps {
+		select {
+		case <-dep.settled:
+		case <-ctx.Done():
+			return false
+		}
 
-	if err := cmd.Start(); err != nil {
-		fmt.Printf("failed to start command: [%s]\n", commandLabel)
-		return
+		if !dep.ready {
+			reason := fmt.Sprintf("dependency [%s] is not ready: %s", dep.command.Label, dep.reason)
+			proc.setStatus(StatusNotStarted)
+			proc.event("not started: %s", reason)
+			proc.settle(false, reason)
+			return false
+		}
 	}
 
-	stdoutScanner := bufio.NewScanner(stdout)
-	stderrScanner := bufio.NewScanner(stderr)
+	return true
+}
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		defer func() {
-			fmt.Printf("%s %s exited.\n", label, command.Command)
-		}()
-		for stdoutScanner.Scan() {
-			var output string
-			if outputColor != "" {
-				output = utils.Colorize(stdoutScanner.Text(), outputColor)
-			} else {
-				output = stdoutScanner.Text()
+// sleep ждет перед автоматическим перезапуском. Возвращает false,
+// если перезапуск отменен остановкой команды или всего алиаса.
+func (p *Process) sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-timer.C:
+			return true
+		case <-p.ctrl:
+			switch p.takeAction() {
+			case actionRestart:
+				return true
+			case actionStop:
+				p.setStatus(StatusStopped)
+				p.event("stopped")
+				return false
 			}
+		}
+	}
+}
 
-			if !withoutOutput {
-				fmt.Printf("%s %s\n", label, output)
+// waitRestart ждет ручного перезапуска команды.
+func (p *Process) waitRestart(ctx context.Context) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-p.ctrl:
+			if p.takeAction() == actionRestart {
+				p.event("restarting")
+				return true
 			}
 		}
-	}()
+	}
+}
+
+// run один запуск команды. onStart вызывается сразу после успешного старта процесса.
+func run(proc *Process, cmd *exec.Cmd, onStart func()) error {
+	if proc.command.TTY {
+		err := runTTY(proc, cmd, onStart)
+		if !errors.Is(err, errNoTTY) {
+			return err
+		}
+
+		// NOTE: например, на windows или в контейнере без /dev/ptmx
+		proc.event("%v; running without tty", err)
+	}
 
-	wg.Add(1)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = nil, nil, nil
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		proc.event("failed to start command: %v", err)
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	proc.started(cmd, done)
+	onStart()
+
+	var stdoutReader, stderrReader io.Reader = stdout, stderr
+	if proc.logs != nil {
+		stdoutReader = io.TeeReader(stdout, proc.logs.stdout)
+		stderrReader = io.TeeReader(stderr, proc.logs.stderr)
+	}
+
+	outWg := &sync.WaitGroup{}
+	outWg.Add(2)
 	go func() {
-		defer wg.Done()
-		for stderrScanner.Scan() {
-			if !withoutOutput {
-				fmt.Printf("%s %s\n", utils.Colorize(fmt.Sprintf("[%s]", command.Label), command.Color), stderrScanner.Text())
+		defer outWg.Done()
+		readOutput(proc, stdoutReader, false)
+	}()
+	go func() {
+		defer outWg.Done()
+		readOutput(proc, stderrReader, true)
+	}()
+
+	outWg.Wait()
+	return cmd.Wait()
+}
+
+// watchReady ждет готовности команды согласно ready_when.
+func watchReady(ctx context.Context, proc *Process) {
+	probe := proc.command.ReadyWhen
+
+	switch {
+	case probe == nil:
+		proc.settle(true, "")
+	case probe.logRe != nil:
+		// сама строка ищется в readOutput, здесь только таймаут
+		timer := time.NewTimer(probe.Timeout)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			reason := fmt.Sprintf("%s is not ready after %s", probe, probe.Timeout)
+			if proc.settle(false, reason) {
+				proc.event("readiness probe failed: %s", reason)
 			}
+		case <-proc.settled:
+		case <-ctx.Done():
 		}
-	}()
+	default:
+		if err := probe.wait(ctx, proc.command.Path); err != nil {
+			if ctx.Err() == nil && proc.settle(false, err.Error()) {
+				proc.event("readiness probe failed: %s", err)
+			}
+			return
+		}
+
+		if proc.settle(true, "") {
+			proc.event("ready: %s", probe)
+		}
+	}
+}
+
+func readOutput(proc *Process, r io.Reader, stderr bool) {
+	probe := proc.command.ReadyWhen
+
+	err := readLines(r, func(output string) {
+		if probe != nil && probe.logRe != nil && probe.logRe.MatchString(output) {
+			if proc.settle(true, "") {
+				proc.event("ready: %s", probe)
+			}
+		}
+
+		proc.out.Line(proc, output, stderr)
+	})
+	if err != nil && !errors.Is(err, os.ErrClosed) && !errors.Is(err, syscall.EIO) {
+		logger.SaveDebugf("[%s] failed to read output: %v", proc.command.Label, err)
+	}
 }
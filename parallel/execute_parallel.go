@@ -1,70 +1,273 @@
 package parallel
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/algrvvv/ali/utils"
 )
 
+// варианты интерфейса для параллельных алиасов
+const (
+	UIStream = "stream"
+	UITui    = "tui"
+	UITmux   = "tmux"
+)
+
+type Options struct {
+	PrintResultCommands bool
+	OutputColor         string
+	WithoutOutput       bool
+	UI                  string
+	TmuxLayout          string
+	OutputMode          string
+	// Timestamps метки времени перед строками вывода: wall или relative
+	Timestamps string
+	// LogDir дублировать вывод команд в файлы в этой директории ("true" - ~/.ali/runs)
+	LogDir string
+
+	startedAt time.Time
+}
+
+// ExecuteParallel запускает команды параллельного алиаса и возвращает код завершения.
+// Все команды собираются по одному контексту запуска inv, который не изменяется.
 func ExecuteParallel(
-	entry *utils.AliasEntry, params []string,
-	flags map[string]string, envs map[string]any,
-	printResultCommands bool,
-) {
-	wg := &sync.WaitGroup{}
-	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
+	entry *utils.AliasEntry, commands []Command,
+	inv *utils.Invocation, opts *Options,
+) int {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	switch opts.UI {
+	case "", UIStream, UITui, UITmux:
+	default:
+		fmt.Printf("unsupported ui: %q (use: %s, %s, %s)\n", opts.UI, UIStream, UITui, UITmux)
+		return 1
+	}
+
+	switch opts.OutputMode {
+	case "", OutputStream, OutputGrouped:
+	default:
+		fmt.Printf("unsupported output mode: %q (use: %s, %s)\n", opts.OutputMode, OutputStream, OutputGrouped)
+		return 1
+	}
+
+	switch opts.Timestamps {
+	case "", TimestampsWall, TimestampsRelative:
+	default:
+		fmt.Printf("unsupported timestamps: %q (use: %s, %s)\n", opts.Timestamps, TimestampsWall, TimestampsRelative)
+		return 1
+	}
+	opts.startedAt = time.Now()
+
+	if len(commands) == 0 {
+		fmt.Printf("alias %q has no commands\n", entry.AliasName)
+		return 1
+	}
+
+	// NOTE: prepare заполняет значения по умолчанию, а команды принадлежат вызывающему
+	commands = slices.Clone(commands)
+
+	if err := CheckOrder(commands); err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	var dash *dashboard
+	var out Output = &streamOutput{opts: opts}
+	if opts.OutputMode == OutputGrouped {
+		out = newGroupedOutput(opts)
+	}
+
+	if opts.UI == UITui {
+		if canUseDashboard() {
+			dash = newDashboard(entry.AliasName)
+			out = dash
+		} else {
+			fmt.Println("tui requires a terminal; using stream output")
+		}
+	}
+
+	procs := make(map[string]*Process, len(commands))
+	list := make([]*Process, 0, len(commands))
+	for i := range commands {
+		command := &commands[i]
+		if err := command.prepare(); err != nil {
+			fmt.Printf("[%s] %v\n", command.Label, err)
+			return 1
+		}
+
+		// в tmux и с tty у команды есть терминал, значит, его можно дать и удаленной команде
+		executor := utils.NewExecutor(command.Host, command.TTY || opts.UI == UITmux)
+		cmd, err := utils.PrepareCommand(
+			executor,
+			inv,
+			command.Command,
+			command.Path,
+			opts.PrintResultCommands,
+		)
+		if err != nil {
+			fmt.Printf("failed to prepare command: [%s]: %v\n", command.Label, err)
+			return 1
+		}
+
+		// елси нет четко задонного цвета логов, пытаемся сохранить исходный.
+		// в tmux и с tty у команды и так будет терминал
+		if opts.OutputColor == "" && opts.UI != UITmux && !command.TTY {
+			cmd.Env = append(cmd.Env, "FORCE_COLOR=1")
+		}
+
+		proc := NewProcess(*command, cmd, out)
+		if dash != nil {
+			// в интерфейсе команды останавливает сам ali,
+			// поэтому каждая запускается в своей группе процессов.
+			// NOTE: команда с tty и так запускается в своей сессии
+			if !command.TTY {
+				utils.SetProcessGroup(cmd)
+			}
+			proc.keepAlive = true
+		}
+
+		procs[command.Label] = proc
+		list = append(list, proc)
+	}
+
+	if opts.LogDir != "" && opts.UI != UITmux {
+		dir, err := RunLogsDir(opts.LogDir, entry.AliasName, opts.startedAt)
+		if err != nil {
+			utils.PrintError("failed to get logs directory", err)
+			return 1
+		}
+
+		for _, proc := range list {
+			if proc.logs, err = openProcessLogs(dir, proc.command.Label); err != nil {
+				utils.PrintError("failed to open log files", err)
+				return 1
+			}
+			defer proc.logs.Close()
+		}
+
+		if dash == nil {
+			fmt.Printf("logs: %s\n", dir)
+		}
+	}
+
+	if opts.UI == UITmux {
+		if err := runTmux(TmuxSessionName(entry.AliasName), opts.TmuxLayout, list); err != nil {
+			utils.PrintError("failed to run commands in tmux", err)
+			return 1
+		}
+		return 0
+	}
 
-	if printResultCommands {
+	if opts.PrintResultCommands && dash == nil {
 		fmt.Println("Configured commands:")
-		for _, cmd := range entry.Cmds {
-			fmt.Printf("[%s] -> %s\n", fmt.Sprintf("%s%s%s", utils.Colors["blue"], entry.AliasName, utils.Colors["reset"]), cmd)
+		for _, cmd := range commands {
+			fmt.Printf("[%s] -> %s\n", utils.Colorize(cmd.Label, cmd.Color), cmd.Command)
 		}
 		fmt.Println(strings.Repeat("=", 30))
 		fmt.Println()
 	}
 
-	for _, command := range entry.Cmds {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wg := &sync.WaitGroup{}
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalChan)
+
+	if dash != nil {
+		dash.procs = list
+	}
+
+	go watchResize(ctx, list)
+
+	for _, command := range commands {
+		deps := make([]*Process, 0, len(command.StartAfter))
+		for _, dep := range command.StartAfter {
+			deps = append(deps, procs[dep])
+		}
+
 		wg.Add(1)
+		go Exec(ctx, procs[command.Label], deps, wg)
+	}
+
+	if dash != nil {
 		go func() {
-			defer wg.Done()
-
-			cmd, err := utils.PrepareCommand(
-				command,
-				entry.Dir,
-				params,
-				flags,
-				envs,
-				printResultCommands,
-			)
-			if err != nil {
-				fmt.Printf("failed to prepare command: [%s]\n", command)
-				return
-			}
+			<-signalChan
+			cancel()
+		}()
 
-			if err := cmd.Start(); err != nil {
-				fmt.Printf("failed to start command: [%s]\n", command)
-				return
-			}
+		if err := dash.run(ctx); err != nil {
+			utils.PrintError("failed to start tui", err)
+		}
 
-			if err := cmd.Wait(); err != nil {
-				fmt.Printf("failed to start command: [%s]\n", command)
-				return
-			}
-		}()
+		// код считаем до остановки: выход из интерфейса не делает команды упавшими
+		code := exitCode(list)
+
+		fmt.Println("stopping commands...")
+		cancel()
+		for _, proc := range list {
+			proc.Stop()
+		}
+		wg.Wait()
+		return code
 	}
 
+	interrupted := make(chan struct{})
 	go func() {
-		<-signalChan
+		select {
+		case <-signalChan:
+		case <-ctx.Done():
+			return
+		}
+
 		fmt.Println("got interrupt...")
-		wg.Wait()
-		os.Exit(1)
+		close(interrupted)
+		// после прерывания команды больше не перезапускаются
+		cancel()
+		// команды с tty в своей сессии и не получают Ctrl+C от терминала
+		for _, proc := range list {
+			if proc.command.TTY {
+				proc.Stop()
+			}
+		}
 	}()
 
 	wg.Wait()
+
+	select {
+	case <-interrupted:
+		return 130
+	default:
+		return exitCode(list)
+	}
+}
+
+// exitCode код завершения алиаса: первый ненулевой код среди команд.
+func exitCode(procs []*Process) int {
+	for _, proc := range procs {
+		state := proc.State()
+		switch {
+		case state.Status == StatusStopped:
+			// остановлена вручную - не ошибка
+		case state.Status == StatusNotStarted:
+			return 1
+		case state.Exited && state.ExitCode > 0:
+			return state.ExitCode
+		case state.Exited && state.ExitCode < 0:
+			return 1
+		}
+	}
+
+	return 0
 }
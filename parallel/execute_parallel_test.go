@@ -0,0 +1,75 @@
+package parallel_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/algrvvv/ali/parallel"
+	"github.com/algrvvv/ali/utils"
+)
+
+// запускается с go test -race: команды собираются по общему контексту запуска
+func TestExecuteParallel(t *testing.T) {
+	dir := t.TempDir()
+
+	var commands []parallel.Command
+	for i := range 8 {
+		commands = append(commands, parallel.Command{
+			Label:   fmt.Sprintf("cmd%d", i),
+			Command: fmt.Sprintf("echo {{name}}-%d", i),
+		})
+	}
+
+	vars := map[string]string{"name": "global"}
+	inv := utils.NewInvocation(utils.InvocationOptions{
+		Vars:  vars,
+		Flags: map[string]string{"-V_name": "override"},
+	})
+
+	entry := &utils.AliasEntry{AliasName: "dev", Parallel: true}
+	code := parallel.ExecuteParallel(entry, commands, inv, &parallel.Options{LogDir: dir})
+	if code != 0 {
+		t.Fatalf("ERROR: want: exit code 0; got: %d", code)
+	}
+
+	for i, command := range commands {
+		files, _ := filepath.Glob(filepath.Join(dir, "dev", "*", command.Label+".stdout.log"))
+		if len(files) != 1 {
+			t.Fatalf("ERROR: want: one log file for %s; got: %v", command.Label, files)
+		}
+
+		data, err := os.ReadFile(files[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := fmt.Sprintf("override-%d", i)
+		if got := strings.TrimSpace(string(data)); got != expected {
+			t.Errorf("ERROR: want: %s; got: %s", expected, got)
+		} else {
+			t.Logf("SUCCESS! [%s] %s", command.Label, got)
+		}
+	}
+
+	if vars["name"] != "global" || inv.Vars()["name"] != "global" {
+		t.Errorf("ERROR: -V_ override leaked into vars: %v, %v", vars, inv.Vars())
+	}
+
+	if commands[0].Restart != "" {
+		t.Errorf("ERROR: commands of the caller are changed: %+v", commands[0])
+	}
+}
+
+func TestExecuteParallelEmpty(t *testing.T) {
+	inv := utils.NewInvocation(utils.InvocationOptions{})
+	entry := &utils.AliasEntry{AliasName: "empty", Parallel: true}
+
+	if code := parallel.ExecuteParallel(entry, nil, inv, &parallel.Options{UI: parallel.UITui}); code != 1 {
+		t.Errorf("ERROR: want: exit code 1; got: %d", code)
+	} else {
+		t.Logf("SUCCESS! alias without commands is rejected")
+	}
+}
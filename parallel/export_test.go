@@ -0,0 +1,84 @@
+package parallel
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/creack/pty"
+)
+
+// внутренние функции пакета для внешних тестов
+var (
+	PrepareCommand = (*Command).prepare
+	ShouldRestart  = (*Command).shouldRestart
+	RestartBackoff = (*Command).backoff
+
+	PrepareProbe = (*Probe).prepare
+	ProbeCheck   = (*Probe).check
+	ProbeWait    = (*Probe).wait
+)
+
+var (
+	NewDashboard       = newDashboard
+	DashboardHandleKey = (*dashboard).handleKey
+	DashboardCurrent   = (*dashboard).current
+	ParseKeys          = parseKeys
+	FitLine            = fitLine
+	Highlight          = highlight
+)
+
+func SetDashboardProcs(d *dashboard, procs []*Process) { d.procs = procs }
+
+func DashboardLine(d *dashboard, proc *Process, line string) { d.Line(proc, line, false) }
+
+func DashboardState(d *dashboard) (selected, scroll, match int, focused bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.selected, d.scroll, d.match, d.focused
+}
+
+var TmuxCommand = tmuxCommand
+
+func StartTmux(run func(args ...string) (string, error), session, layout string, procs []*Process) error {
+	return startTmux(run, session, layout, procs)
+}
+
+func NewGroupedOutput(opts *Options, ci string, w io.Writer) Output {
+	out := newGroupedOutput(opts)
+	out.ci, out.w = ci, w
+	return out
+}
+
+func SetProcessStarted(p *Process, at time.Time) {
+	p.mu.Lock()
+	p.startedAt = at
+	p.mu.Unlock()
+}
+
+var ReadLines = readLines
+
+func OpenRotatingFile(path string) (io.WriteCloser, error) {
+	f, err := openRotatingFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return rotatingCloser{f}, nil
+}
+
+type rotatingCloser struct{ *rotatingFile }
+
+func (c rotatingCloser) Close() error {
+	c.rotatingFile.Close()
+	return nil
+}
+
+// WithoutPTY имитирует систему без псевдотерминалов до вызова restore.
+func WithoutPTY() (restore func()) {
+	prev := startPTY
+	startPTY = func(*exec.Cmd, *pty.Winsize) (*os.File, error) { return nil, pty.ErrUnsupported }
+	return func() { startPTY = prev }
+}
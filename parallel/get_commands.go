@@ -0,0 +1,141 @@
+package parallel
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+const ParallelPrefix = "parallel"
+
+// политики перезапуска команд
+const (
+	RestartNo        = "no"
+	RestartOnFailure = "on-failure"
+	RestartAlways    = "always"
+)
+
+const (
+	defaultBackoff = time.Second
+	maxBackoff     = 30 * time.Second
+)
+
+// Command описание одной команды параллельного алиаса.
+// Задается в секции `parallel.<alias>` конфигурации.
+type Command struct {
+	Label      string   `mapstructure:"label"`
+	Color      string   `mapstructure:"color"`
+	Command    string   `mapstructure:"command"`
+	Path       string   `mapstructure:"path"`
+	ReadyWhen  *Probe   `mapstructure:"ready_when"`
+	StartAfter []string `mapstructure:"start_after"`
+
+	Restart     string        `mapstructure:"restart"`
+	MaxRestarts int           `mapstructure:"max_restarts"`
+	Backoff     time.Duration `mapstructure:"backoff"`
+
+	// TTY запускать команду в псевдотерминале
+	TTY bool `mapstructure:"tty"`
+	// Host хост для запуска команды по ssh, по умолчанию host алиаса
+	Host string `mapstructure:"host"`
+}
+
+// prepare проверяет настройки команды и заполняет значения по умолчанию.
+func (c *Command) prepare() error {
+	switch c.Restart {
+	case "", "0", "false":
+		c.Restart = RestartNo
+	case RestartNo, RestartOnFailure, RestartAlways:
+	default:
+		return fmt.Errorf("unsupported restart policy: %q (use: no, on-failure, always)", c.Restart)
+	}
+
+	if c.MaxRestarts < 0 {
+		return fmt.Errorf("max_restarts must not be negative: %d", c.MaxRestarts)
+	}
+
+	if c.Backoff <= 0 {
+		c.Backoff = defaultBackoff
+	}
+
+	if c.ReadyWhen != nil {
+		return c.ReadyWhen.prepare()
+	}
+
+	return nil
+}
+
+// shouldRestart решает по политике restart, нужно ли перезапускать команду,
+// завершившуюся с кодом code. Ограничение max_restarts здесь не учитывается.
+func (c *Command) shouldRestart(code int) bool {
+	switch c.Restart {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return code != 0
+	default:
+		return false
+	}
+}
+
+// backoff задержка перед очередным перезапуском: удваивается с каждым разом, но не больше maxBackoff.
+func (c *Command) backoff(restarts int) time.Duration {
+	delay := c.Backoff
+	for i := 1; i < restarts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxBackoff)
+}
+
+// цвета, которые по очереди выдаются командам без явно заданного цвета
+var defaultColors = []string{"blue", "green", "magenta", "cyan", "orange", "pink", "yellow", "lime"}
+
+// GetCommands собирает команды параллельного алиаса: сначала строки из `cmds`,
+// а затем подробно описанные команды из секции `parallel.<alias>` конфигурации v.
+func GetCommands(v *viper.Viper, entry *utils.AliasEntry) ([]Command, error) {
+	commands := make([]Command, 0, len(entry.Cmds))
+	for _, c := range entry.Cmds {
+		commands = append(commands, Command{Command: c})
+	}
+
+	var configured []Command
+	key := fmt.Sprintf("%s.%s", ParallelPrefix, entry.AliasName)
+	if err := v.UnmarshalKey(key, &configured); err != nil {
+		return nil, fmt.Errorf("failed to get parallel commands for %q: %w", entry.AliasName, err)
+	}
+	commands = append(commands, configured...)
+
+	for i := range commands {
+		if commands[i].Label == "" {
+			commands[i].Label = fmt.Sprintf("%s#%d", entry.AliasName, i+1)
+		}
+
+		if commands[i].Color == "" {
+			commands[i].Color = defaultColors[i%len(defaultColors)]
+		}
+
+		if commands[i].Path == "" {
+			commands[i].Path = entry.Dir
+		}
+
+		if commands[i].Host == "" {
+			commands[i].Host = entry.Host
+		}
+
+		// tty: true у алиаса включает терминал для всех его команд
+		if entry.TTY {
+			commands[i].TTY = true
+		}
+	}
+
+	return commands, nil
+}
+
+// HasCommands проверяет, есть ли для алиаса секция `parallel.<alias>` в конфигурации v.
+func HasCommands(v *viper.Viper, aliasName string) bool {
+	return v.IsSet(fmt.Sprintf("%s.%s", ParallelPrefix, aliasName))
+}
@@ -0,0 +1,111 @@
+package parallel_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/algrvvv/ali/parallel"
+	"github.com/algrvvv/ali/utils"
+)
+
+func TestRestartPolicy(t *testing.T) {
+	tests := []struct {
+		restart string
+		code    int
+		want    bool
+	}{
+		{"", 1, false},
+		{"false", 1, false},
+		{parallel.RestartNo, 1, false},
+		{parallel.RestartOnFailure, 0, false},
+		{parallel.RestartOnFailure, 1, true},
+		{parallel.RestartOnFailure, -1, true},
+		{parallel.RestartAlways, 0, true},
+		{parallel.RestartAlways, 2, true},
+	}
+
+	for _, test := range tests {
+		command := parallel.Command{Restart: test.restart}
+		if err := parallel.PrepareCommand(&command); err != nil {
+			t.Errorf("ERROR: restart %q: %v", test.restart, err)
+			continue
+		}
+
+		if got := parallel.ShouldRestart(&command, test.code); got != test.want {
+			t.Errorf("ERROR: restart %q, code %d: want: %v; got: %v", test.restart, test.code, test.want, got)
+		} else {
+			t.Logf("SUCCESS! restart %q, code %d: %v", test.restart, test.code, got)
+		}
+	}
+
+	for _, command := range []parallel.Command{{Restart: "sometimes"}, {MaxRestarts: -1}} {
+		if err := parallel.PrepareCommand(&command); err == nil {
+			t.Errorf("ERROR: want error for %+v", command)
+		}
+	}
+}
+
+func TestRestartBackoff(t *testing.T) {
+	tests := []struct {
+		backoff  time.Duration
+		restarts int
+		want     time.Duration
+	}{
+		{0, 1, time.Second},
+		{0, 2, 2 * time.Second},
+		{0, 3, 4 * time.Second},
+		{0, 6, 30 * time.Second},
+		{0, 100, 30 * time.Second},
+		{100 * time.Millisecond, 1, 100 * time.Millisecond},
+		{100 * time.Millisecond, 4, 800 * time.Millisecond},
+		{time.Minute, 1, 30 * time.Second},
+	}
+
+	for _, test := range tests {
+		command := parallel.Command{Backoff: test.backoff}
+		if err := parallel.PrepareCommand(&command); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := parallel.RestartBackoff(&command, test.restarts); got != test.want {
+			t.Errorf("ERROR: backoff %s, restart %d: want: %s; got: %s", test.backoff, test.restarts, test.want, got)
+		} else {
+			t.Logf("SUCCESS! backoff %s, restart %d: %s", test.backoff, test.restarts, got)
+		}
+	}
+}
+
+func TestMaxRestarts(t *testing.T) {
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+
+	commands := []parallel.Command{{
+		Label:       "flaky",
+		Command:     "echo run >> " + runs + "; exit 3",
+		Restart:     parallel.RestartOnFailure,
+		MaxRestarts: 2,
+		Backoff:     10 * time.Millisecond,
+	}}
+
+	inv := utils.NewInvocation(utils.InvocationOptions{})
+	entry := &utils.AliasEntry{AliasName: "flaky", Parallel: true}
+	code := parallel.ExecuteParallel(entry, commands, inv, &parallel.Options{WithoutOutput: true})
+	if code != 3 {
+		t.Errorf("ERROR: want: exit code 3; got: %d", code)
+	}
+
+	data, err := os.ReadFile(runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// первый запуск и два перезапуска
+	if got := strings.Count(string(data), "run"); got != 3 {
+		t.Errorf("ERROR: want: 3 runs; got: %d", got)
+	} else {
+		t.Logf("SUCCESS! runs: %d", got)
+	}
+}
@@ -0,0 +1,133 @@
+package parallel
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+// режимы вывода параллельных команд
+const (
+	OutputStream  = "stream"
+	OutputGrouped = "grouped"
+)
+
+const (
+	ciGitHub = "github"
+	ciGitLab = "gitlab"
+)
+
+var sectionNameRe = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// groupedOutput копит вывод каждой команды и печатает его одним блоком,
+// когда команда завершается. Удобно для логов CI, где строки не должны перемешиваться.
+type groupedOutput struct {
+	opts *Options
+	ci   string
+	w    io.Writer
+
+	mu      sync.Mutex
+	buffers map[*Process][]string
+	runs    map[*Process]int
+}
+
+func newGroupedOutput(opts *Options) *groupedOutput {
+	return &groupedOutput{
+		opts:    opts,
+		ci:      detectCI(),
+		w:       os.Stdout,
+		buffers: make(map[*Process][]string),
+		runs:    make(map[*Process]int),
+	}
+}
+
+// detectCI определяет CI, в котором запущен ali, для маркеров сворачиваемых групп.
+func detectCI() string {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return ciGitHub
+	case os.Getenv("GITLAB_CI") == "true":
+		return ciGitLab
+	default:
+		return ""
+	}
+}
+
+func (o *groupedOutput) Line(proc *Process, line string, stderr bool) {
+	if o.opts.WithoutOutput {
+		return
+	}
+
+	if !stderr && o.opts.OutputColor != "" {
+		line = utils.Colorize(line, o.opts.OutputColor)
+	}
+
+	o.mu.Lock()
+	o.buffers[proc] = append(o.buffers[proc], o.opts.stamp(line))
+	o.mu.Unlock()
+}
+
+func (o *groupedOutput) Event(proc *Process, msg string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	fmt.Fprintf(o.w, "%s %s\n", proc.label(), o.opts.stamp(msg))
+}
+
+func (o *groupedOutput) Finished(proc *Process, code int, duration time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	lines := o.buffers[proc]
+	delete(o.buffers, proc)
+	o.runs[proc]++
+
+	title := fmt.Sprintf("[%s] %s (exit code %d, %s)",
+		proc.command.Label, proc.command.Command, code, duration.Round(time.Millisecond))
+	if run := o.runs[proc]; run > 1 {
+		title += fmt.Sprintf(" run %d", run)
+	}
+
+	var b strings.Builder
+	switch o.ci {
+	case ciGitHub:
+		b.WriteString("::group::" + title + "\n")
+	case ciGitLab:
+		section := sectionNameRe.ReplaceAllString(fmt.Sprintf("ali_%s_%d", proc.command.Label, o.runs[proc]), "_")
+		collapsed := ""
+		if code == 0 {
+			collapsed = "[collapsed=true]"
+		}
+		// NOTE: длительность секции GitLab считает по этим меткам, поэтому начало - запуск команды
+		startedAt := proc.State().StartedAt
+		if startedAt.IsZero() {
+			startedAt = time.Now().Add(-duration)
+		}
+		fmt.Fprintf(&b, "\x1b[0Ksection_start:%d:%s%s\r\x1b[0K%s\n", startedAt.Unix(), section, collapsed, title)
+		defer func() {
+			fmt.Fprintf(o.w, "\x1b[0Ksection_end:%d:%s\r\x1b[0K\n", time.Now().Unix(), section)
+		}()
+	default:
+		clr := "green"
+		if code != 0 {
+			clr = "red"
+		}
+		b.WriteString(utils.Colorize(fmt.Sprintf("==== %s ====", title), clr) + "\n")
+	}
+
+	for _, line := range lines {
+		b.WriteString(line + "\n")
+	}
+
+	if o.ci == ciGitHub {
+		b.WriteString("::endgroup::\n")
+	}
+
+	fmt.Fprint(o.w, b.String())
+}
@@ -0,0 +1,75 @@
+package parallel_test
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/algrvvv/ali/parallel"
+)
+
+func TestGroupedOutput(t *testing.T) {
+	startedAt := time.Now().Add(-90 * time.Second)
+
+	tests := []struct {
+		ci   string
+		code int
+		want []string
+	}{
+		{
+			ci: "github",
+			want: []string{
+				"::group::[api] go run . (exit code 0, 1m30s)",
+				"line 1",
+				"line 2",
+				"::endgroup::",
+			},
+		},
+		{
+			ci:   "gitlab",
+			code: 1,
+			want: []string{
+				fmt.Sprintf("\x1b[0Ksection_start:%d:ali_api_1\r\x1b[0K[api] go run . (exit code 1, 1m30s)", startedAt.Unix()),
+				"line 1",
+				"line 2",
+				"\x1b[0Ksection_end:",
+			},
+		},
+		{
+			ci:   "gitlab",
+			want: []string{fmt.Sprintf("section_start:%d:ali_api_1[collapsed=true]", startedAt.Unix())},
+		},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		out := parallel.NewGroupedOutput(&parallel.Options{}, test.ci, &buf)
+
+		proc := parallel.NewProcess(parallel.Command{Label: "api", Command: "go run ."}, exec.Command("true"), out)
+		parallel.SetProcessStarted(proc, startedAt)
+
+		out.Line(proc, "line 1", false)
+		out.Line(proc, "line 2", true)
+		if buf.Len() != 0 {
+			t.Errorf("ERROR: %s: output is printed before the command finished: %q", test.ci, buf.String())
+		}
+		out.Finished(proc, test.code, 90*time.Second)
+
+		got := buf.String()
+		pos := 0
+		for _, want := range test.want {
+			idx := strings.Index(got[pos:], want)
+			if idx < 0 {
+				t.Errorf("ERROR: %s: want %q after position %d in:\n%q", test.ci, want, pos, got)
+				break
+			}
+			pos += idx + len(want)
+		}
+		if !t.Failed() {
+			t.Logf("SUCCESS! %s: %q", test.ci, got)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package parallel
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+// Output приемник вывода команд параллельного алиаса.
+type Output interface {
+	// Line строка вывода команды
+	Line(proc *Process, line string, stderr bool)
+	// Event служебное сообщение о команде: запуск, готовность, перезапуск и т.д.
+	Event(proc *Process, msg string)
+	// Finished очередной запуск команды завершился
+	Finished(proc *Process, code int, duration time.Duration)
+}
+
+// streamOutput общий поток вывода, где каждая строка помечена [label].
+type streamOutput struct {
+	opts *Options
+}
+
+func (o *streamOutput) Line(proc *Process, line string, stderr bool) {
+	if o.opts.WithoutOutput {
+		return
+	}
+
+	if !stderr && o.opts.OutputColor != "" {
+		line = utils.Colorize(line, o.opts.OutputColor)
+	}
+
+	fmt.Printf("%s %s\n", proc.label(), o.opts.stamp(line))
+}
+
+func (o *streamOutput) Event(proc *Process, msg string) {
+	fmt.Printf("%s %s\n", proc.label(), o.opts.stamp(msg))
+}
+
+func (o *streamOutput) Finished(*Process, int, time.Duration) {}
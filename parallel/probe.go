@@ -0,0 +1,158 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultProbeTimeout  = 60 * time.Second
+	defaultProbeInterval = 500 * time.Millisecond
+)
+
+// Probe проверка готовности команды (ready_when).
+// Должен быть задан ровно один из вариантов: port, http, log или file.
+type Probe struct {
+	Port     int           `mapstructure:"port"`
+	Host     string        `mapstructure:"host"`
+	HTTP     string        `mapstructure:"http"`
+	Log      string        `mapstructure:"log"`
+	File     string        `mapstructure:"file"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+	Interval time.Duration `mapstructure:"interval"`
+
+	logRe *regexp.Regexp
+}
+
+var errInvalidProbe = errors.New("ready_when must contain exactly one of: port, http, log, file")
+
+func (p *Probe) prepare() error {
+	var kinds int
+	for _, set := range []bool{p.Port != 0, p.HTTP != "", p.Log != "", p.File != ""} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return errInvalidProbe
+	}
+
+	if p.Log != "" {
+		re, err := regexp.Compile(p.Log)
+		if err != nil {
+			return fmt.Errorf("invalid ready_when log regexp: %w", err)
+		}
+		p.logRe = re
+	}
+
+	if p.Host == "" {
+		p.Host = "localhost"
+	}
+	if p.Timeout <= 0 {
+		p.Timeout = defaultProbeTimeout
+	}
+	if p.Interval <= 0 {
+		p.Interval = defaultProbeInterval
+	}
+
+	return nil
+}
+
+func (p *Probe) String() string {
+	switch {
+	case p.Port != 0:
+		return "port " + net.JoinHostPort(p.Host, strconv.Itoa(p.Port))
+	case p.HTTP != "":
+		return "http " + p.HTTP
+	case p.Log != "":
+		return fmt.Sprintf("log line matching %q", p.Log)
+	default:
+		return "file " + p.File
+	}
+}
+
+// check выполняет одну попытку проверки. log проверки здесь не обрабатываются,
+// они срабатывают при чтении вывода команды.
+func (p *Probe) check(ctx context.Context, dir string) bool {
+	switch {
+	case p.Port != 0:
+		d := net.Dialer{Timeout: p.Interval}
+		conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(p.Host, strconv.Itoa(p.Port)))
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	case p.HTTP != "":
+		reqCtx, cancel := context.WithTimeout(ctx, p.Interval*4)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, p.HTTP, nil)
+		if err != nil {
+			return false
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	case p.File != "":
+		_, err := os.Stat(probePath(p.File, dir))
+		return err == nil
+	}
+
+	return false
+}
+
+// wait опрашивает проверку до успеха, таймаута или отмены контекста.
+func (p *Probe) wait(ctx context.Context, dir string) error {
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		if p.check(ctx, dir) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("%s is not ready after %s", p, p.Timeout)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func probePath(path, dir string) string {
+	if strings.HasPrefix(path, "~") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = strings.Replace(path, "~", home, 1)
+		}
+	}
+
+	if !filepath.IsAbs(path) && dir != "" {
+		if strings.HasPrefix(dir, "~") {
+			if home, err := os.UserHomeDir(); err == nil {
+				dir = strings.Replace(dir, "~", home, 1)
+			}
+		}
+		path = filepath.Join(dir, path)
+	}
+
+	return path
+}
@@ -0,0 +1,201 @@
+package parallel_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/algrvvv/ali/parallel"
+	"github.com/algrvvv/ali/utils"
+)
+
+func TestProbePrepare(t *testing.T) {
+	tests := []struct {
+		name    string
+		probe   parallel.Probe
+		wantErr bool
+	}{
+		{name: "port", probe: parallel.Probe{Port: 8080}},
+		{name: "log", probe: parallel.Probe{Log: `listening on \d+`}},
+		{name: "empty", probe: parallel.Probe{}, wantErr: true},
+		{name: "two kinds", probe: parallel.Probe{Port: 8080, File: "ready"}, wantErr: true},
+		{name: "invalid regexp", probe: parallel.Probe{Log: "("}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		err := parallel.PrepareProbe(&test.probe)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ERROR: %s: want error: %v; got: %v", test.name, test.wantErr, err)
+		} else {
+			t.Logf("SUCCESS! %s: %v", test.name, err)
+		}
+	}
+
+	probe := parallel.Probe{Port: 8080}
+	_ = parallel.PrepareProbe(&probe)
+	if probe.Host != "localhost" || probe.Timeout != 60*time.Second || probe.Interval != 500*time.Millisecond {
+		t.Errorf("ERROR: want: default host, timeout and interval; got: %+v", probe)
+	}
+}
+
+// checkProbe готовит проверку и выполняет одну попытку.
+func checkProbe(t *testing.T, probe parallel.Probe, dir string) bool {
+	t.Helper()
+
+	if err := parallel.PrepareProbe(&probe); err != nil {
+		t.Fatal(err)
+	}
+
+	return parallel.ProbeCheck(&probe, context.Background(), dir)
+}
+
+func TestProbePort(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	if !checkProbe(t, parallel.Probe{Host: "127.0.0.1", Port: port}, "") {
+		t.Errorf("ERROR: want: port %d is ready", port)
+	}
+
+	listener.Close()
+	if checkProbe(t, parallel.Probe{Host: "127.0.0.1", Port: port, Interval: 100 * time.Millisecond}, "") {
+		t.Errorf("ERROR: want: closed port %d is not ready", port)
+	} else {
+		t.Logf("SUCCESS! port probe")
+	}
+}
+
+func TestProbeHTTP(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	probe := parallel.Probe{HTTP: server.URL + "/health"}
+	if checkProbe(t, probe, "") {
+		t.Errorf("ERROR: want: %d is not ready", status.Load())
+	}
+
+	status.Store(http.StatusOK)
+	if !checkProbe(t, probe, "") {
+		t.Errorf("ERROR: want: %d is ready", status.Load())
+	} else {
+		t.Logf("SUCCESS! http probe")
+	}
+}
+
+func TestProbeFile(t *testing.T) {
+	dir := t.TempDir()
+	probe := parallel.Probe{File: "tmp/ready"}
+
+	if checkProbe(t, probe, dir) {
+		t.Errorf("ERROR: want: missing file is not ready")
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "tmp"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tmp", "ready"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// относительный путь считается от директории команды
+	if !checkProbe(t, probe, dir) {
+		t.Errorf("ERROR: want: file in command dir is ready")
+	} else {
+		t.Logf("SUCCESS! file probe")
+	}
+}
+
+func TestProbeTimeout(t *testing.T) {
+	probe := parallel.Probe{File: "never", Timeout: 200 * time.Millisecond, Interval: 50 * time.Millisecond}
+	if err := parallel.PrepareProbe(&probe); err != nil {
+		t.Fatal(err)
+	}
+
+	err := parallel.ProbeWait(&probe, context.Background(), t.TempDir())
+	if err == nil || err.Error() != "file never is not ready after 200ms" {
+		t.Errorf("ERROR: want: timeout error; got: %v", err)
+	} else {
+		t.Logf("SUCCESS! got: %v", err)
+	}
+}
+
+// runStartAfter запускает api после db и возвращает вывод api.
+func runStartAfter(t *testing.T, db parallel.Command) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	logs := t.TempDir()
+	db.Label, db.Path = "db", dir
+	commands := []parallel.Command{
+		db,
+		{
+			Label:      "api",
+			Path:       dir,
+			Command:    "if test -f db.ready; then echo after; else echo before; fi",
+			StartAfter: []string{"db"},
+		},
+	}
+
+	inv := utils.NewInvocation(utils.InvocationOptions{})
+	entry := &utils.AliasEntry{AliasName: "stack", Parallel: true}
+	parallel.ExecuteParallel(entry, commands, inv, &parallel.Options{LogDir: logs, WithoutOutput: true})
+
+	files, _ := filepath.Glob(filepath.Join(logs, "stack", "*", "api.stdout.log"))
+	if len(files) == 0 {
+		return ""
+	}
+
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.TrimSpace(string(data))
+}
+
+func TestStartAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		db       parallel.Command
+		expected string
+	}{
+		{
+			name:     "log",
+			db:       parallel.Command{Command: "sleep 0.3; touch db.ready; echo db is up", ReadyWhen: &parallel.Probe{Log: "is up"}},
+			expected: "after",
+		},
+		{
+			name:     "file",
+			db:       parallel.Command{Command: "sleep 0.3; touch db.ready; sleep 0.5", ReadyWhen: &parallel.Probe{File: "db.ready", Interval: 50 * time.Millisecond}},
+			expected: "after",
+		},
+		{
+			// db не стал готов за timeout - api не запускается
+			name:     "not ready",
+			db:       parallel.Command{Command: "sleep 0.5", ReadyWhen: &parallel.Probe{Log: "never", Timeout: 100 * time.Millisecond}},
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		if got := runStartAfter(t, test.db); got != test.expected {
+			t.Errorf("ERROR: %s: want: %q; got: %q", test.name, test.expected, got)
+		} else {
+			t.Logf("SUCCESS! %s: api output: %q", test.name, got)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package parallel
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+// Status состояние команды параллельного алиаса.
+type Status string
+
+const (
+	StatusWaiting    Status = "waiting"
+	StatusRunning    Status = "running"
+	StatusReady      Status = "ready"
+	StatusRestarting Status = "restarting"
+	StatusExited     Status = "exited"
+	StatusFailed     Status = "failed"
+	StatusStopped    Status = "stopped"
+	StatusNotStarted Status = "not started"
+)
+
+// время, которое дается процессу на завершение после SIGTERM
+const stopTimeout = 5 * time.Second
+
+type action int
+
+const (
+	actionNone action = iota
+	actionRestart
+	actionStop
+)
+
+// State снимок состояния команды.
+type State struct {
+	Label     string
+	Color     string
+	Command   string
+	Status    Status
+	StartedAt time.Time
+	Restarts  int
+	ExitCode  int
+	Exited    bool
+}
+
+// Process запущенная (или ожидающая запуска) команда параллельного алиаса.
+type Process struct {
+	command Command
+	cmd     *exec.Cmd
+	out     Output
+
+	// keepAlive - после завершения не выходить, а ждать ручного перезапуска
+	keepAlive bool
+	// logs - файлы, в которые дублируется вывод команды (log_dir)
+	logs *processLogs
+
+	// settled закрывается, когда становится известно, готова команда или нет
+	settled chan struct{}
+	ready   bool
+	reason  string
+	once    sync.Once
+
+	mu        sync.Mutex
+	status    Status
+	startedAt time.Time
+	restarts  int
+	exitCode  int
+	exited    bool
+	current   *exec.Cmd
+	done      chan struct{}
+	tty       *os.File
+	pending   action
+	ctrl      chan struct{}
+}
+
+func NewProcess(command Command, cmd *exec.Cmd, out Output) *Process {
+	return &Process{
+		command: command,
+		cmd:     cmd,
+		out:     out,
+		settled: make(chan struct{}),
+		status:  StatusWaiting,
+		ctrl:    make(chan struct{}, 1),
+	}
+}
+
+// settle фиксирует готовность команды. Срабатывает только один раз,
+// возвращает false, если состояние уже было зафиксировано ранее.
+func (p *Process) settle(ready bool, reason string) bool {
+	var ok bool
+	p.once.Do(func() {
+		p.mu.Lock()
+		p.ready = ready
+		p.reason = reason
+		if ready && p.status == StatusRunning {
+			p.status = StatusReady
+		}
+		p.mu.Unlock()
+
+		close(p.settled)
+		ok = true
+	})
+
+	return ok
+}
+
+func (p *Process) label() string {
+	return utils.Colorize(fmt.Sprintf("[%s]", p.command.Label), p.command.Color)
+}
+
+func (p *Process) event(format string, args ...any) {
+	p.out.Event(p, fmt.Sprintf(format, args...))
+}
+
+func (p *Process) State() State {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return State{
+		Label:     p.command.Label,
+		Color:     p.command.Color,
+		Command:   p.command.Command,
+		Status:    p.status,
+		StartedAt: p.startedAt,
+		Restarts:  p.restarts,
+		ExitCode:  p.exitCode,
+		Exited:    p.exited,
+	}
+}
+
+func (p *Process) setStatus(status Status) {
+	p.mu.Lock()
+	p.status = status
+	p.mu.Unlock()
+}
+
+func (p *Process) started(cmd *exec.Cmd, done chan struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.current = cmd
+	p.done = done
+	p.startedAt = time.Now()
+	p.status = StatusRunning
+	if p.ready {
+		p.status = StatusReady
+	}
+}
+
+func (p *Process) finished(code int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.current = nil
+	p.done = nil
+	p.exitCode = code
+	p.exited = true
+	p.status = StatusExited
+	if code != 0 {
+		p.status = StatusFailed
+	}
+}
+
+// takeAction забирает действие, запрошенное через Restart или Stop.
+func (p *Process) takeAction() action {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	a := p.pending
+	p.pending = actionNone
+	return a
+}
+
+// Restart перезапускает команду (или запускает, если она уже завершилась).
+func (p *Process) Restart() {
+	p.request(actionRestart)
+}
+
+// Stop останавливает команду вместе со всеми дочерними процессами.
+func (p *Process) Stop() {
+	p.request(actionStop)
+}
+
+func (p *Process) request(a action) {
+	p.mu.Lock()
+	p.pending = a
+	cmd, done := p.current, p.done
+	p.mu.Unlock()
+
+	if cmd != nil && cmd.Process != nil {
+		terminate(cmd, done)
+	}
+
+	select {
+	case p.ctrl <- struct{}{}:
+	default:
+	}
+}
+
+// terminate завершает дерево процессов команды: сначала мягко, а если
+// за stopTimeout процесс не завершился - принудительно.
+func terminate(cmd *exec.Cmd, done chan struct{}) {
+	pid := cmd.Process.Pid
+	if err := utils.TerminateProcessGroup(pid); err != nil {
+		_ = cmd.Process.Kill()
+	}
+
+	go func() {
+		select {
+		case <-done:
+		case <-time.After(stopTimeout):
+			if err := utils.KillProcessGroup(pid); err != nil {
+				_ = cmd.Process.Kill()
+			}
+		}
+	}()
+}
@@ -0,0 +1,43 @@
+package parallel
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+// строки длиннее этого размера выводятся частями, чтобы не держать в памяти бесконечную строку
+const maxLineSize = 1024 * 1024
+
+// readLines читает r построчно и вызывает fn для каждой строки без '\n'.
+// В отличие от bufio.Scanner не останавливается на длинных строках,
+// а последняя строка без переноса тоже передается в fn.
+func readLines(r io.Reader, fn func(line string)) error {
+	reader := bufio.NewReaderSize(r, 64*1024)
+
+	var line strings.Builder
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		line.Write(chunk)
+
+		switch {
+		case err == nil:
+			fn(strings.TrimSuffix(strings.TrimSuffix(line.String(), "\n"), "\r"))
+			line.Reset()
+		case errors.Is(err, bufio.ErrBufferFull):
+			if line.Len() >= maxLineSize {
+				fn(line.String())
+				line.Reset()
+			}
+		default:
+			if line.Len() > 0 {
+				fn(line.String())
+			}
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
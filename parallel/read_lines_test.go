@@ -0,0 +1,54 @@
+package parallel_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/algrvvv/ali/parallel"
+)
+
+func TestReadLines(t *testing.T) {
+	long := strings.Repeat("x", 200*1024)
+	huge := strings.Repeat("y", 1024*1024+10)
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"simple", "a\nb\n", []string{"a", "b"}},
+		{"crlf", "a\r\nb\r\n", []string{"a", "b"}},
+		{"no trailing newline", "a\nb", []string{"a", "b"}},
+		{"empty lines", "\n\na\n", []string{"", "", "a"}},
+		// NOTE: bufio.Scanner на такой строке останавливается с ErrTooLong
+		{"line longer than 64KB", "start\n" + long + "\nend\n", []string{"start", long, "end"}},
+	}
+
+	for _, test := range tests {
+		var got []string
+		err := parallel.ReadLines(strings.NewReader(test.input), func(line string) {
+			got = append(got, line)
+		})
+		if err != nil {
+			t.Errorf("ERROR: %s: %v", test.name, err)
+			continue
+		}
+
+		if strings.Join(got, "|") != strings.Join(test.want, "|") || len(got) != len(test.want) {
+			t.Errorf("ERROR: %s: want %d lines; got %d", test.name, len(test.want), len(got))
+		} else {
+			t.Logf("SUCCESS! %s: %d lines", test.name, len(got))
+		}
+	}
+
+	// строка больше 1MB выводится частями, но без потерь
+	var parts []string
+	err := parallel.ReadLines(strings.NewReader(huge+"\n"), func(line string) {
+		parts = append(parts, line)
+	})
+	if err != nil || len(parts) < 2 || strings.Join(parts, "") != huge {
+		t.Errorf("ERROR: huge line: want it split without losses; got %d parts (%v)", len(parts), err)
+	} else {
+		t.Logf("SUCCESS! huge line: %d parts", len(parts))
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package parallel
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyResize подписывает ch на изменение размера окна терминала.
+func notifyResize(ch chan os.Signal) bool {
+	signal.Notify(ch, syscall.SIGWINCH)
+	return true
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package parallel
+
+import "os"
+
+// notifyResize на windows сигнала об изменении размера окна нет, как и pty.
+func notifyResize(chan os.Signal) bool {
+	return false
+}
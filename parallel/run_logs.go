@@ -0,0 +1,161 @@
+package parallel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/algrvvv/ali/logger"
+)
+
+// ограничения для файлов логов: после maxLogSize файл ротируется,
+// хранится не больше maxLogFiles файлов на поток.
+const (
+	maxLogSize  = 10 * 1024 * 1024
+	maxLogFiles = 3
+)
+
+// RunLogsDir директория логов запуска: <base>/<alias>/<run-id>.
+// Если base пустой или "true" - используется ~/.ali/runs.
+func RunLogsDir(base, alias string, startedAt time.Time) (string, error) {
+	if base == "" || base == "true" || base == "1" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, ".ali", "runs")
+	} else if strings.HasPrefix(base, "~") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = strings.Replace(base, "~", home, 1)
+	}
+
+	runID := fmt.Sprintf("%s-%d", startedAt.Format("20060102-150405"), os.Getpid())
+	return filepath.Join(base, safeFileName(alias), runID), nil
+}
+
+// processLogs файлы stdout и stderr одной команды.
+type processLogs struct {
+	stdout *rotatingFile
+	stderr *rotatingFile
+}
+
+func openProcessLogs(dir, label string) (*processLogs, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	name := safeFileName(label)
+	stdout, err := openRotatingFile(filepath.Join(dir, name+".stdout.log"))
+	if err != nil {
+		return nil, err
+	}
+
+	stderr, err := openRotatingFile(filepath.Join(dir, name+".stderr.log"))
+	if err != nil {
+		stdout.Close()
+		return nil, err
+	}
+
+	return &processLogs{stdout: stdout, stderr: stderr}, nil
+}
+
+func (l *processLogs) Close() {
+	l.stdout.Close()
+	l.stderr.Close()
+}
+
+func safeFileName(name string) string {
+	return strings.NewReplacer("/", "_", "\\", "_", ":", "_", " ", "_").Replace(name)
+}
+
+// rotatingFile файл, который при превышении maxLogSize переименовывается
+// в file.1 (file.1 в file.2 и т.д.), а запись продолжается в новый файл.
+type rotatingFile struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	size int64
+}
+
+func openRotatingFile(path string) (*rotatingFile, error) {
+	f := &rotatingFile{path: path}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// Write никогда не возвращает ошибку: проблемы с логами
+// не должны мешать выводу самой команды.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return len(p), nil
+	}
+
+	if f.size > 0 && f.size+int64(len(p)) > maxLogSize {
+		if err := f.rotate(); err != nil {
+			logger.SaveDebugf("failed to rotate log file %s: %v", f.path, err)
+			return len(p), nil
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if err != nil {
+		logger.SaveDebugf("failed to write log file %s: %v", f.path, err)
+	}
+
+	return len(p), nil
+}
+
+func (f *rotatingFile) rotate() error {
+	f.file.Close()
+	f.file = nil
+
+	for i := maxLogFiles - 1; i > 0; i-- {
+		from := f.path
+		if i > 1 {
+			from = fmt.Sprintf("%s.%d", f.path, i-1)
+		}
+		_ = os.Rename(from, fmt.Sprintf("%s.%d", f.path, i))
+	}
+
+	return f.open()
+}
+
+func (f *rotatingFile) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file != nil {
+		f.file.Close()
+		f.file = nil
+	}
+}
@@ -0,0 +1,52 @@
+package parallel_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/algrvvv/ali/parallel"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.stdout.log")
+
+	f, err := parallel.OpenRotatingFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// каждый кусок 6MB: второй уже не помещается в 10MB и вызывает ротацию
+	chunk := func(b byte) []byte { return bytes.Repeat([]byte{b}, 6*1024*1024) }
+	for _, b := range []byte("abcd") {
+		if _, err := f.Write(chunk(b)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.Close()
+
+	want := map[string]byte{
+		path:        'd',
+		path + ".1": 'c',
+		path + ".2": 'b',
+	}
+	for file, b := range want {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Errorf("ERROR: %v", err)
+			continue
+		}
+
+		if !bytes.Equal(data, chunk(b)) {
+			t.Errorf("ERROR: %s: want only %q (%d bytes); got %d bytes", filepath.Base(file), b, 6*1024*1024, len(data))
+		} else {
+			t.Logf("SUCCESS! %s: %q", filepath.Base(file), b)
+		}
+	}
+
+	// хранится не больше трех файлов
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("ERROR: want: no %s.3; got: %v", filepath.Base(path), err)
+	}
+}
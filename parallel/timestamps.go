@@ -0,0 +1,26 @@
+package parallel
+
+import (
+	"fmt"
+	"time"
+)
+
+// форматы меток времени перед строками вывода
+const (
+	TimestampsWall     = "wall"
+	TimestampsRelative = "relative"
+)
+
+// stamp добавляет к строке метку времени согласно opts.Timestamps.
+func (o *Options) stamp(line string) string {
+	now := time.Now()
+
+	switch o.Timestamps {
+	case TimestampsWall:
+		return now.Format("15:04:05.000") + " " + line
+	case TimestampsRelative:
+		return fmt.Sprintf("+%.3fs %s", now.Sub(o.startedAt).Seconds(), line)
+	default:
+		return line
+	}
+}
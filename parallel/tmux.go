@@ -0,0 +1,176 @@
+package parallel
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/algrvvv/ali/logger"
+)
+
+// TmuxWindows раскладка по умолчанию: каждая команда в своем окне.
+// Любое другое значение - это раскладка tmux (tiled, even-horizontal, ...)
+// для панелей в одном окне.
+const TmuxWindows = "windows"
+
+var errTmuxNotFound = errors.New("tmux not found in PATH")
+
+// TmuxSessionName имя tmux сессии для алиаса. В имя входит текущая директория,
+// чтобы одинаковые алиасы разных проектов не попадали в одну сессию.
+func TmuxSessionName(alias string) string {
+	name := "ali-" + alias
+	if wd, err := os.Getwd(); err == nil {
+		name = fmt.Sprintf("ali-%s-%s", filepath.Base(wd), alias)
+	}
+
+	return strings.NewReplacer(".", "-", ":", "-", " ", "-").Replace(name)
+}
+
+// runTmux запускает каждую команду в своем окне (или панели) tmux сессии
+// и подключается к ней. Если сессия уже есть - просто подключается.
+func runTmux(session, layout string, procs []*Process) error {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return errTmuxNotFound
+	}
+
+	if _, err := tmux("has-session", "-t", "="+session); err == nil {
+		fmt.Printf("tmux session %q already exists; attaching\n", session)
+		return attachTmux(session)
+	}
+
+	for _, proc := range procs {
+		c := proc.command
+		if len(c.StartAfter) > 0 || c.ReadyWhen != nil || c.Restart != RestartNo {
+			fmt.Printf("[%s] start_after, ready_when and restart are not supported with ui: tmux\n", c.Label)
+		}
+	}
+
+	if err := startTmux(tmux, session, layout, procs); err != nil {
+		return err
+	}
+
+	fmt.Printf("tmux session %q created\n", session)
+	return attachTmux(session)
+}
+
+// tmuxFunc выполняет команду tmux и возвращает ее вывод.
+type tmuxFunc func(args ...string) (string, error)
+
+// startTmux создает сессию с окнами (или панелями) команд, не подключаясь к ней.
+func startTmux(tmux tmuxFunc, session, layout string, procs []*Process) error {
+	if layout == "" {
+		layout = TmuxWindows
+	}
+
+	var firstWindow string
+	for i, proc := range procs {
+		dir, err := tmuxDir(proc.cmd.Dir)
+		if err != nil {
+			return err
+		}
+		argv := tmuxCommand(proc.cmd)
+
+		var target string
+		switch {
+		case i == 0:
+			name := proc.command.Label
+			if layout != TmuxWindows {
+				name = strings.TrimPrefix(session, "ali-")
+			}
+
+			args := []string{"new-session", "-d", "-P", "-F", "#{window_id}", "-s", session, "-n", name, "-c", dir}
+			target, err = tmux(append(args, argv...)...)
+			firstWindow = target
+		case layout == TmuxWindows:
+			args := []string{"new-window", "-d", "-P", "-F", "#{window_id}", "-t", session + ":", "-n", proc.command.Label, "-c", dir}
+			target, err = tmux(append(args, argv...)...)
+		default:
+			args := []string{"split-window", "-d", "-P", "-F", "#{pane_id}", "-t", firstWindow, "-c", dir}
+			target, err = tmux(append(args, argv...)...)
+			if err == nil {
+				// перестраиваем раскладку сразу, иначе на новые панели может не хватить места
+				_, err = tmux("select-layout", "-t", firstWindow, layout)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to start [%s] in tmux: %w", proc.command.Label, err)
+		}
+		logger.SaveDebugf("command [%s] started in tmux: %s", proc.command.Label, target)
+
+		if layout != TmuxWindows {
+			// для первой команды target - это окно, а его активная панель как раз нужная
+			_, _ = tmux("select-pane", "-t", target, "-T", proc.command.Label)
+		}
+
+		// не закрываем окно после завершения команды, чтобы был виден ее вывод
+		if i == 0 || layout == TmuxWindows {
+			_, _ = tmux("set-option", "-w", "-t", target, "remain-on-exit", "on")
+		}
+	}
+
+	if layout != TmuxWindows {
+		_, _ = tmux("set-option", "-w", "-t", firstWindow, "pane-border-status", "top")
+		_, _ = tmux("set-option", "-w", "-t", firstWindow, "pane-border-format", " #{pane_title} ")
+	}
+	_, _ = tmux("select-window", "-t", firstWindow)
+
+	return nil
+}
+
+func attachTmux(session string) error {
+	action := "attach-session"
+	// внутри tmux подключение создаст вложенную сессию, поэтому просто переключаемся
+	if os.Getenv("TMUX") != "" {
+		action = "switch-client"
+	}
+
+	cmd := exec.Command("tmux", action, "-t", "="+session)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+func tmux(args ...string) (string, error) {
+	logger.SaveDebugf("tmux %v", args)
+
+	out, err := exec.Command("tmux", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// tmuxCommand собирает команду для окна tmux. Переменные окружения, которые добавил
+// PrepareCommand, передаются через env, так как сервер tmux живет со своим окружением.
+func tmuxCommand(cmd *exec.Cmd) []string {
+	environ := os.Environ()
+
+	var extra []string
+	for _, env := range cmd.Env {
+		if !slices.Contains(environ, env) {
+			extra = append(extra, env)
+		}
+	}
+
+	if len(extra) == 0 {
+		return cmd.Args
+	}
+
+	argv := append([]string{"env"}, extra...)
+	return append(argv, cmd.Args...)
+}
+
+func tmuxDir(dir string) (string, error) {
+	if dir == "" {
+		return os.Getwd()
+	}
+
+	return filepath.Abs(dir)
+}
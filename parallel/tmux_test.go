@@ -0,0 +1,126 @@
+package parallel_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/algrvvv/ali/parallel"
+)
+
+const quotedCommand = `echo "a b" && echo 'c $HOME'`
+
+func tmuxProcs(env ...string) []*parallel.Process {
+	var procs []*parallel.Process
+	for _, label := range []string{"api", "web"} {
+		cmd := exec.Command("sh", "-c", quotedCommand)
+		cmd.Dir = "/srv"
+		cmd.Env = append(os.Environ(), env...)
+		procs = append(procs, parallel.NewProcess(parallel.Command{Label: label}, cmd, nil))
+	}
+
+	return procs
+}
+
+// fakeTmux записывает вызовы tmux и выдает id новых окон и панелей.
+func fakeTmux(calls *[][]string) func(args ...string) (string, error) {
+	var windows, panes int
+	return func(args ...string) (string, error) {
+		*calls = append(*calls, args)
+
+		switch args[0] {
+		case "new-session", "new-window":
+			windows++
+			return fmt.Sprintf("@%d", windows), nil
+		case "split-window":
+			panes++
+			return fmt.Sprintf("%%%d", panes), nil
+		}
+		return "", nil
+	}
+}
+
+func TestTmuxCommand(t *testing.T) {
+	cmd := exec.Command("sh", "-c", quotedCommand)
+	cmd.Env = os.Environ()
+
+	// команда передается tmux как argv, без склейки в строку и повторного экранирования
+	want := []string{"sh", "-c", quotedCommand}
+	if got := parallel.TmuxCommand(cmd); !slices.Equal(got, want) {
+		t.Errorf("ERROR: want: %q; got: %q", want, got)
+	} else {
+		t.Logf("SUCCESS! %q", got)
+	}
+
+	// окружение сервера tmux не наше, поэтому добавленные переменные передаются через env
+	cmd.Env = append(cmd.Env, "FORCE_COLOR=1", "GREETING=hello world")
+	want = []string{"env", "FORCE_COLOR=1", "GREETING=hello world", "sh", "-c", quotedCommand}
+	if got := parallel.TmuxCommand(cmd); !slices.Equal(got, want) {
+		t.Errorf("ERROR: want: %q; got: %q", want, got)
+	} else {
+		t.Logf("SUCCESS! %q", got)
+	}
+}
+
+func TestStartTmuxWindows(t *testing.T) {
+	var calls [][]string
+	err := parallel.StartTmux(fakeTmux(&calls), "ali-app-dev", "", tmuxProcs("FOO=bar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	argv := []string{"env", "FOO=bar", "sh", "-c", quotedCommand}
+	want := [][]string{
+		append([]string{"new-session", "-d", "-P", "-F", "#{window_id}", "-s", "ali-app-dev", "-n", "api", "-c", "/srv"}, argv...),
+		{"set-option", "-w", "-t", "@1", "remain-on-exit", "on"},
+		append([]string{"new-window", "-d", "-P", "-F", "#{window_id}", "-t", "ali-app-dev:", "-n", "web", "-c", "/srv"}, argv...),
+		{"set-option", "-w", "-t", "@2", "remain-on-exit", "on"},
+		{"select-window", "-t", "@1"},
+	}
+
+	checkCalls(t, calls, want)
+}
+
+func TestStartTmuxPanes(t *testing.T) {
+	var calls [][]string
+	err := parallel.StartTmux(fakeTmux(&calls), "ali-app-dev", "tiled", tmuxProcs())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	argv := []string{"sh", "-c", quotedCommand}
+	want := [][]string{
+		append([]string{"new-session", "-d", "-P", "-F", "#{window_id}", "-s", "ali-app-dev", "-n", "app-dev", "-c", "/srv"}, argv...),
+		{"select-pane", "-t", "@1", "-T", "api"},
+		{"set-option", "-w", "-t", "@1", "remain-on-exit", "on"},
+		append([]string{"split-window", "-d", "-P", "-F", "#{pane_id}", "-t", "@1", "-c", "/srv"}, argv...),
+		{"select-layout", "-t", "@1", "tiled"},
+		{"select-pane", "-t", "%1", "-T", "web"},
+		{"set-option", "-w", "-t", "@1", "pane-border-status", "top"},
+		{"set-option", "-w", "-t", "@1", "pane-border-format", " #{pane_title} "},
+		{"select-window", "-t", "@1"},
+	}
+
+	checkCalls(t, calls, want)
+}
+
+func checkCalls(t *testing.T, calls, want [][]string) {
+	t.Helper()
+
+	if !reflect.DeepEqual(calls, want) {
+		var got, expected []string
+		for _, call := range calls {
+			got = append(got, fmt.Sprintf("%q", call))
+		}
+		for _, call := range want {
+			expected = append(expected, fmt.Sprintf("%q", call))
+		}
+		t.Errorf("ERROR: want:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	} else {
+		t.Logf("SUCCESS! %d tmux calls", len(calls))
+	}
+}
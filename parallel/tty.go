@@ -0,0 +1,116 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"github.com/creack/pty"
+	"golang.org/x/term"
+)
+
+// размер терминала команды, если сам ali запущен не в терминале
+const (
+	defaultTTYCols = 120
+	defaultTTYRows = 40
+)
+
+// errNoTTY псевдотерминал недоступен, команду можно запустить без него.
+var errNoTTY = errors.New("tty is not available")
+
+// startPTY запускает команду в новом псевдотерминале.
+var startPTY = pty.StartWithSize
+
+// runTTY один запуск команды в псевдотерминале (tty: true).
+// stdout и stderr в терминале общие, поэтому весь вывод идет как stdout.
+// Если псевдотерминал открыть не удалось, возвращает errNoTTY, а команда не запускается.
+func runTTY(proc *Process, cmd *exec.Cmd, onStart func()) error {
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = nil, nil, nil
+
+	tty, err := startPTY(cmd, terminalSize())
+	if err != nil {
+		if ptyUnavailable(err) {
+			return fmt.Errorf("%w: %v", errNoTTY, err)
+		}
+
+		proc.event("failed to start command in tty: %v", err)
+		return err
+	}
+	defer tty.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	proc.started(cmd, done)
+	proc.setTTY(tty)
+	defer proc.setTTY(nil)
+	onStart()
+
+	var r io.Reader = tty
+	if proc.logs != nil {
+		r = io.TeeReader(tty, proc.logs.stdout)
+	}
+
+	// NOTE: после завершения всех процессов терминала чтение вернет EIO, это обычный конец вывода
+	readOutput(proc, r, false)
+	return cmd.Wait()
+}
+
+// ptyUnavailable ошибка открытия самого псевдотерминала, а не запуска команды.
+func ptyUnavailable(err error) bool {
+	var pathErr *fs.PathError
+	return errors.Is(err, pty.ErrUnsupported) ||
+		(errors.As(err, &pathErr) && pathErr.Path == "/dev/ptmx")
+}
+
+func (p *Process) setTTY(tty *os.File) {
+	p.mu.Lock()
+	p.tty = tty
+	p.mu.Unlock()
+}
+
+// resizeTTY передает команде новый размер терминала.
+func (p *Process) resizeTTY(size *pty.Winsize) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.tty != nil {
+		_ = pty.Setsize(p.tty, size)
+	}
+}
+
+// terminalSize размер терминала, в котором запущен ali.
+func terminalSize() *pty.Winsize {
+	cols, rows, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || cols <= 0 || rows <= 0 {
+		cols, rows = defaultTTYCols, defaultTTYRows
+	}
+
+	return &pty.Winsize{Cols: uint16(cols), Rows: uint16(rows)}
+}
+
+// watchResize пересылает изменения размера окна всем командам с tty.
+func watchResize(ctx context.Context, procs []*Process) {
+	resized := make(chan os.Signal, 1)
+	if !notifyResize(resized) {
+		return
+	}
+	defer signal.Stop(resized)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-resized:
+			size := terminalSize()
+			for _, proc := range procs {
+				proc.resizeTTY(size)
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+//go:build !windows
+
+package parallel_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/algrvvv/ali/parallel"
+	"github.com/algrvvv/ali/utils"
+)
+
+// runTTYCommand запускает команду с tty: true и возвращает ее stdout из лог-файла.
+func runTTYCommand(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	commands := []parallel.Command{{
+		Label:   "term",
+		Command: "if test -t 1; then echo terminal; else echo pipe; fi",
+		TTY:     true,
+	}}
+
+	inv := utils.NewInvocation(utils.InvocationOptions{})
+	entry := &utils.AliasEntry{AliasName: "term", Parallel: true}
+	if code := parallel.ExecuteParallel(entry, commands, inv, &parallel.Options{LogDir: dir, WithoutOutput: true}); code != 0 {
+		t.Fatalf("ERROR: want: exit code 0; got: %d", code)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "term", "*", "term.stdout.log"))
+	if len(files) != 1 {
+		t.Fatalf("ERROR: want: one log file; got: %v", files)
+	}
+
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.TrimSpace(string(data))
+}
+
+func TestTTY(t *testing.T) {
+	if _, err := os.Stat("/dev/ptmx"); err != nil {
+		t.Skip("pty is not available:", err)
+	}
+
+	if got := runTTYCommand(t); got != "terminal" {
+		t.Errorf("ERROR: want: terminal; got: %q", got)
+	} else {
+		t.Logf("SUCCESS! stdout is a terminal")
+	}
+}
+
+func TestTTYFallback(t *testing.T) {
+	defer parallel.WithoutPTY()()
+
+	if got := runTTYCommand(t); got != "pipe" {
+		t.Errorf("ERROR: want: pipe; got: %q", got)
+	} else {
+		t.Logf("SUCCESS! command runs without tty")
+	}
+}
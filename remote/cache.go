@@ -0,0 +1,135 @@
+package remote
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/algrvvv/ali/logger"
+)
+
+// CacheDir директория кэша удаленных конфигов: ~/.ali/cache.
+func CacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".ali", "cache"), nil
+}
+
+// Key источник без пути внутри репозитория: у всех файлов
+// одного репозитория и ref общая директория в кэше.
+func (s *Source) Key() string {
+	if s.Kind == KindGit && s.Ref != "" {
+		return gitPrefix + s.URL + "@" + s.Ref
+	}
+	if s.Kind == KindGit {
+		return gitPrefix + s.URL
+	}
+
+	return s.URL
+}
+
+// dir директория источника в кэше.
+func (s *Source) dir() (string, error) {
+	cache, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(s.Key()))
+
+	return filepath.Join(cache, s.Kind, hex.EncodeToString(sum[:8])), nil
+}
+
+// file путь до конфига в директории источника.
+func (s *Source) file(dir string) string {
+	if s.Kind == KindGit {
+		if s.Path == "" {
+			return dir
+		}
+		return filepath.Join(dir, filepath.FromSlash(s.Path))
+	}
+
+	return filepath.Join(dir, httpFileName(s.URL))
+}
+
+// Resolve локальный путь удаленного конфига. Если источник уже в кэше,
+// сеть не используется, поэтому после первой загрузки конфиги работают офлайн.
+// Для git путь может указывать на директорию или быть шаблоном.
+func Resolve(s *Source) (string, error) {
+	dir, err := s.dir()
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(dir); err == nil {
+		if s.Kind != KindHTTP || s.SHA256 == "" || verify(s.file(dir), s.SHA256) == nil {
+			return s.file(dir), nil
+		}
+		// NOTE: закрепленная сумма изменилась - скачиваем заново
+		logger.SaveDebugf("cached %s doesn't match pin; fetch again", s)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	return Update(s)
+}
+
+// Update скачивает источник заново и заменяет им кэш.
+func Update(s *Source) (string, error) {
+	dir, err := s.dir()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return "", err
+	}
+
+	// качаем во временную директорию, чтобы при ошибке остался старый кэш
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), ".fetch-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	logger.SaveDebugf("fetch %s into %s", s, dir)
+	switch s.Kind {
+	case KindGit:
+		err = fetchGit(s, tmp)
+	default:
+		err = fetchHTTP(s, filepath.Join(tmp, httpFileName(s.URL)))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return "", err
+	}
+
+	return s.file(dir), nil
+}
+
+func httpFileName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "include.yml"
+	}
+
+	name := path.Base(u.Path)
+	if name == "" || name == "." || name == "/" {
+		return "include.yml"
+	}
+
+	return name
+}
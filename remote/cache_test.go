@@ -0,0 +1,97 @@
+package remote_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/algrvvv/ali/remote"
+)
+
+func TestResolveHTTP(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	config := []byte("aliases:\n  team: echo team\n")
+	sum := sha256.Sum256(config)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(config)
+	}))
+
+	source, err := remote.ParseSource(server.URL + "/team.yml#sha256=" + hex.EncodeToString(sum[:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := remote.Resolve(source); err != nil {
+		t.Fatalf("ERROR: failed to fetch: %v", err)
+	}
+
+	// из кэша конфиг берется без сети
+	server.Close()
+	path, err := remote.Resolve(source)
+	if err != nil {
+		t.Fatalf("ERROR: want cached config offline; got: %v", err)
+	}
+
+	if data, err := os.ReadFile(path); err != nil || string(data) != string(config) {
+		t.Errorf("ERROR: want: %q; got: %q (%v)", config, data, err)
+	} else {
+		t.Logf("SUCCESS! got cached config: %s", path)
+	}
+
+	source.SHA256 = hex.EncodeToString(make([]byte, 32))
+	if _, err := remote.Resolve(source); err == nil {
+		t.Errorf("ERROR: want error for changed pin without network")
+	}
+}
+
+func TestResolveGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	repo := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=ali", "-c", "user.email=ali@localhost"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	write := func(content string) {
+		if err := os.WriteFile(filepath.Join(repo, "aliases.yml"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write("aliases:\n  pack: echo v1\n")
+	run("add", ".")
+	run("commit", "-qm", "v1")
+	run("tag", "v1")
+	write("aliases:\n  pack: echo v2\n")
+	run("commit", "-qam", "v2")
+
+	source, err := remote.ParseSource("git+file://" + filepath.ToSlash(repo) + "//aliases.yml@v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := remote.Resolve(source)
+	if err != nil {
+		t.Fatalf("ERROR: failed to fetch: %v", err)
+	}
+
+	if data, err := os.ReadFile(path); err != nil || string(data) != "aliases:\n  pack: echo v1\n" {
+		t.Errorf("ERROR: want config from tag v1; got: %q (%v)", data, err)
+	} else {
+		t.Logf("SUCCESS! got config from tag v1: %s", path)
+	}
+}
@@ -0,0 +1,45 @@
+package remote
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// fetchGit скачивает ref репозитория в dir системным git,
+// поэтому работают настройки git, ~/.ssh/config и ssh-agent.
+func fetchGit(s *Source, dir string) error {
+	ref := s.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	steps := [][]string{
+		{"init", "-q"},
+		{"fetch", "-q", "--depth", "1", "--", s.URL, ref},
+		{"-c", "advice.detachedHead=false", "checkout", "-q", "FETCH_HEAD"},
+	}
+	for _, args := range steps {
+		if err := git(dir, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func git(dir string, args ...string) error {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	// NOTE: без терминала git не должен ждать ввода пароля
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package remote
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	httpTimeout = 30 * time.Second
+	// максимальный размер скачиваемого конфига
+	maxConfigSize = 10 << 20
+)
+
+func fetchHTTP(s *Source, path string) error {
+	client := &http.Client{Timeout: httpTimeout}
+	resp, err := client.Get(s.URL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: %s", s.URL, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxConfigSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxConfigSize {
+		return fmt.Errorf("config %s is larger than %d bytes", s.URL, maxConfigSize)
+	}
+
+	if s.SHA256 != "" {
+		if got := checksum(data); got != s.SHA256 {
+			return fmt.Errorf("sha256 mismatch for %s: want %s, got %s", s.URL, s.SHA256, got)
+		}
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+func verify(path string, sum string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if got := checksum(data); got != sum {
+		return fmt.Errorf("sha256 mismatch for %s: want %s, got %s", path, sum, got)
+	}
+
+	return nil
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
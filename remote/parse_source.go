@@ -0,0 +1,124 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const (
+	KindGit  = "git"
+	KindHTTP = "http"
+)
+
+const gitPrefix = "git+"
+
+// Source удаленный конфиг из include.
+//
+//	git+https://host/team/ali-pack.git//aliases.yml@v1.2
+//	https://host/team.yml#sha256=<hex>
+type Source struct {
+	Kind string
+	// URL репозиторий для git или адрес файла для http
+	URL string
+	// Path путь до конфига внутри репозитория
+	Path string
+	// Ref ветка, тег или коммит, по умолчанию HEAD
+	Ref string
+	// SHA256 ожидаемая сумма файла, скачанного по http
+	SHA256 string
+}
+
+// IsRemote проверяет, указывает ли include на удаленный конфиг.
+func IsRemote(include string) bool {
+	return strings.HasPrefix(include, gitPrefix) ||
+		strings.HasPrefix(include, "https://") ||
+		strings.HasPrefix(include, "http://")
+}
+
+// ParseSource разбирает удаленный include.
+func ParseSource(include string) (*Source, error) {
+	if strings.HasPrefix(include, gitPrefix) {
+		return parseGit(strings.TrimPrefix(include, gitPrefix))
+	}
+
+	if strings.HasPrefix(include, "https://") || strings.HasPrefix(include, "http://") {
+		return parseHTTP(include)
+	}
+
+	return nil, fmt.Errorf("unsupported remote include: %q", include)
+}
+
+func parseGit(raw string) (*Source, error) {
+	scheme := strings.Index(raw, "://")
+	if scheme < 0 {
+		return nil, fmt.Errorf("invalid git include: %q (want git+<url>//<path>@<ref>)", raw)
+	}
+	rest := raw[scheme+3:]
+
+	s := &Source{Kind: KindGit}
+
+	// NOTE: @ до первого / - это пользователь (git+ssh://git@host/...), а не ref
+	if at := strings.LastIndex(rest, "@"); at > strings.Index(rest, "/") && strings.Contains(rest, "/") {
+		s.Ref = rest[at+1:]
+		rest = rest[:at]
+		if s.Ref == "" {
+			return nil, fmt.Errorf("empty ref in git include: %q", raw)
+		}
+	}
+
+	// repo.git//path/in/repo; для file:/// путь начинается с /, поэтому ищем после него
+	if sep := strings.Index(rest[1:], "//"); sep >= 0 {
+		s.Path = strings.Trim(rest[sep+3:], "/")
+		rest = rest[:sep+1]
+	}
+
+	s.URL = raw[:scheme+3] + rest
+	if slices.Contains(strings.Split(s.Path, "/"), "..") {
+		return nil, fmt.Errorf("path in git include must not leave the repository: %q", s.Path)
+	}
+
+	return s, nil
+}
+
+func parseHTTP(raw string) (*Source, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Source{Kind: KindHTTP}
+	if u.Fragment != "" {
+		sum, ok := strings.CutPrefix(u.Fragment, "sha256=")
+		if !ok || len(sum) != 64 {
+			return nil, fmt.Errorf("invalid pin in %q (want #sha256=<64 hex chars>)", raw)
+		}
+		s.SHA256 = strings.ToLower(sum)
+		u.Fragment = ""
+	}
+
+	if u.Host == "" {
+		return nil, errors.New("empty host in http include")
+	}
+	s.URL = u.String()
+
+	return s, nil
+}
+
+func (s *Source) String() string {
+	switch s.Kind {
+	case KindGit:
+		out := gitPrefix + s.URL
+		if s.Path != "" {
+			out += "//" + s.Path
+		}
+		if s.Ref != "" {
+			out += "@" + s.Ref
+		}
+		return out
+	default:
+		return s.URL
+	}
+}
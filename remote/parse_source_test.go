@@ -0,0 +1,52 @@
+package remote_test
+
+import (
+	"testing"
+
+	"github.com/algrvvv/ali/remote"
+)
+
+func TestParseSource(t *testing.T) {
+	sum := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+	tests := []struct {
+		input    string
+		expected remote.Source
+		err      bool
+	}{
+		{
+			input:    "git+https://host/team/ali-pack.git//aliases.yml@v1.2",
+			expected: remote.Source{Kind: remote.KindGit, URL: "https://host/team/ali-pack.git", Path: "aliases.yml", Ref: "v1.2"},
+		},
+		{
+			input:    "git+ssh://git@host/team/ali-pack.git",
+			expected: remote.Source{Kind: remote.KindGit, URL: "ssh://git@host/team/ali-pack.git"},
+		},
+		{
+			input:    "git+file:///srv/pack.git//aliases.d/*.yml",
+			expected: remote.Source{Kind: remote.KindGit, URL: "file:///srv/pack.git", Path: "aliases.d/*.yml"},
+		},
+		{
+			input:    "https://host/team.yml#sha256=" + sum,
+			expected: remote.Source{Kind: remote.KindHTTP, URL: "https://host/team.yml", SHA256: sum},
+		},
+		{input: "https://host/team.yml#md5=1", err: true},
+		{input: "git+https://host/pack.git//../etc/passwd", err: true},
+	}
+
+	for _, test := range tests {
+		got, err := remote.ParseSource(test.input)
+		if test.err {
+			if err == nil {
+				t.Errorf("ERROR: %s: want error; got: %+v", test.input, got)
+			}
+			continue
+		}
+
+		if err != nil || *got != test.expected {
+			t.Errorf("ERROR: %s: want: %+v; got: %+v (%v)", test.input, test.expected, got, err)
+		} else {
+			t.Logf("SUCCESS! %s: got: %+v", test.input, *got)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// что делать с запусками, пропущенными, пока демон не работал
+const (
+	MissedSkip = "skip"
+	MissedRun  = "run"
+)
+
+// минимальный интервал для every
+const minInterval = time.Second
+
+// Config расписание алиаса. В конфигурации задается строкой
+// ("0 3 * * *", "@daily", "@every 30m", "30m") или объектом:
+//
+//	schedule:
+//	  cron: "0 3 * * *"
+//	  missed: run
+type Config struct {
+	Cron  string        `mapstructure:"cron"`
+	Every time.Duration `mapstructure:"every"`
+	// Missed skip (по умолчанию) - пропущенные запуски не выполняются,
+	// run - после старта демона пропущенный запуск выполняется один раз
+	Missed string `mapstructure:"missed"`
+
+	cron *Cron
+}
+
+// ParseSpec разбирает расписание, заданное строкой.
+func ParseSpec(spec string) (*Config, error) {
+	spec = strings.TrimSpace(spec)
+	if every, ok := strings.CutPrefix(spec, "@every "); ok {
+		spec = strings.TrimSpace(every)
+	}
+
+	if d, err := time.ParseDuration(spec); err == nil {
+		return &Config{Every: d}, nil
+	}
+
+	return &Config{Cron: spec}, nil
+}
+
+// DecodeHook позволяет задавать schedule строкой.
+func DecodeHook() mapstructure.DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.String || to != reflect.TypeOf(Config{}) {
+			return data, nil
+		}
+
+		cfg, err := ParseSpec(data.(string))
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"cron": cfg.Cron, "every": cfg.Every}, nil
+	}
+}
+
+// Prepare проверяет расписание и заполняет значения по умолчанию.
+func (c *Config) Prepare() error {
+	switch {
+	case c.Cron != "" && c.Every != 0:
+		return errors.New("schedule: use only one of cron and every")
+	case c.Cron != "":
+		cron, err := ParseCron(c.Cron)
+		if err != nil {
+			return err
+		}
+		c.cron = cron
+	case c.Every != 0:
+		if c.Every < minInterval {
+			return fmt.Errorf("schedule: interval %s is too small", c.Every)
+		}
+	default:
+		return errors.New("schedule: cron or every is required")
+	}
+
+	switch c.Missed {
+	case "":
+		c.Missed = MissedSkip
+	case MissedSkip, MissedRun:
+	default:
+		return fmt.Errorf("schedule: unsupported missed value: %q (use: %s, %s)", c.Missed, MissedSkip, MissedRun)
+	}
+
+	return nil
+}
+
+// Next время следующего запуска после t.
+func (c *Config) Next(t time.Time) time.Time {
+	if c.cron != nil {
+		return c.cron.Next(t)
+	}
+
+	return t.Add(c.Every)
+}
+
+func (c *Config) String() string {
+	if c.Cron != "" {
+		return c.Cron
+	}
+
+	return "@every " + c.Every.String()
+}
@@ -0,0 +1,232 @@
+package schedule
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// если запуск опоздал больше чем на это время (например, компьютер спал),
+// он считается пропущенным
+const missedGrace = time.Minute
+
+// Job алиас, который нужно запускать по расписанию.
+type Job struct {
+	Alias    string
+	Schedule *Config
+}
+
+type job struct {
+	Job
+	next time.Time
+}
+
+// Daemon запускает алиасы по расписанию. Один и тот же алиас
+// не запускается повторно, пока не завершился предыдущий запуск.
+type Daemon struct {
+	// Dir директория, в которой работает демон
+	Dir string
+	// Run выполняет алиас и возвращает код завершения
+	Run func(alias string) int
+	// Logf вывод сообщений демона
+	Logf func(format string, args ...any)
+
+	statePath string
+	jobs      map[string]*job
+	reload    chan []Job
+
+	// mu защищает state и running: их меняют завершившиеся запуски
+	mu      sync.Mutex
+	state   State
+	running map[string]bool
+	wg      sync.WaitGroup
+}
+
+func NewDaemon(dir string, run func(alias string) int, logf func(format string, args ...any)) (*Daemon, error) {
+	path, err := StatePath()
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := loadState(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Daemon{
+		Dir:       dir,
+		Run:       run,
+		Logf:      logf,
+		statePath: path,
+		state:     state,
+		jobs:      make(map[string]*job),
+		reload:    make(chan []Job, 1),
+		running:   make(map[string]bool),
+	}, nil
+}
+
+// SetJobs заменяет список алиасов с расписанием, например, после изменения конфигурации.
+func (d *Daemon) SetJobs(jobs []Job) {
+	select {
+	case <-d.reload:
+	default:
+	}
+	d.reload <- jobs
+}
+
+// Loop основной цикл демона. Завершается при отмене ctx,
+// дождавшись уже запущенных алиасов.
+func (d *Daemon) Loop(ctx context.Context) {
+	defer d.wg.Wait()
+
+	for {
+		// NOTE: если запускать нечего, nil канал просто никогда не сработает
+		var timer *time.Timer
+		var fired <-chan time.Time
+		if next, ok := d.nextRun(); ok {
+			timer = time.NewTimer(time.Until(next))
+			fired = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case jobs := <-d.reload:
+			d.apply(jobs, time.Now())
+		case <-fired:
+			d.runDue(time.Now())
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// Plan выводит, когда будут запущены алиасы, ничего не запуская.
+func (d *Daemon) Plan(jobs []Job) {
+	d.apply(jobs, time.Now())
+}
+
+func (d *Daemon) sortedJobs() []*job {
+	jobs := make([]*job, 0, len(d.jobs))
+	for _, j := range d.jobs {
+		jobs = append(jobs, j)
+	}
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].Alias < jobs[k].Alias })
+	return jobs
+}
+
+func (d *Daemon) nextRun() (time.Time, bool) {
+	var next time.Time
+	for _, j := range d.jobs {
+		if !j.next.IsZero() && (next.IsZero() || j.next.Before(next)) {
+			next = j.next
+		}
+	}
+
+	return next, !next.IsZero()
+}
+
+// apply применяет новый список алиасов и определяет время их следующего запуска.
+func (d *Daemon) apply(jobs []Job, now time.Time) {
+	updated := make(map[string]*job, len(jobs))
+	for _, newJob := range jobs {
+		// расписание не поменялось - сохраняем уже посчитанное время
+		if old, ok := d.jobs[newJob.Alias]; ok && old.Schedule.String() == newJob.Schedule.String() &&
+			old.Schedule.Missed == newJob.Schedule.Missed {
+			updated[newJob.Alias] = old
+			continue
+		}
+
+		j := &job{Job: newJob}
+		updated[newJob.Alias] = j
+
+		d.mu.Lock()
+		last, ok := d.state[stateKey(d.Dir, newJob.Alias)]
+		d.mu.Unlock()
+		if !ok {
+			j.next = newJob.Schedule.Next(now)
+			d.Logf("%s: scheduled %s, next run at %s", newJob.Alias, newJob.Schedule, formatTime(j.next))
+			continue
+		}
+
+		j.next = newJob.Schedule.Next(last)
+		if !j.next.IsZero() && j.next.Before(now) {
+			if newJob.Schedule.Missed == MissedRun {
+				d.Logf("%s: missed run at %s, running now", newJob.Alias, formatTime(j.next))
+				j.next = now
+				continue
+			}
+
+			d.Logf("%s: missed run at %s, skipping", newJob.Alias, formatTime(j.next))
+			j.next = newJob.Schedule.Next(now)
+		}
+		d.Logf("%s: scheduled %s, next run at %s", newJob.Alias, newJob.Schedule, formatTime(j.next))
+	}
+
+	for alias := range d.jobs {
+		if _, ok := updated[alias]; !ok {
+			d.Logf("%s: removed from schedule", alias)
+		}
+	}
+
+	d.jobs = updated
+}
+
+// runDue запускает алиасы, время которых уже наступило.
+func (d *Daemon) runDue(now time.Time) {
+	for _, j := range d.sortedJobs() {
+		if !j.next.IsZero() && !j.next.After(now) {
+			d.due(j, now)
+		}
+	}
+}
+
+func (d *Daemon) due(j *job, now time.Time) {
+	scheduled := j.next
+	j.next = j.Schedule.Next(now)
+
+	if now.Sub(scheduled) > missedGrace && j.Schedule.Missed == MissedSkip {
+		d.Logf("%s: missed run at %s, skipping; next run at %s", j.Alias, formatTime(scheduled), formatTime(j.next))
+		return
+	}
+
+	d.mu.Lock()
+	if d.running[j.Alias] {
+		d.mu.Unlock()
+		d.Logf("%s: previous run is still running, skipping; next run at %s", j.Alias, formatTime(j.next))
+		return
+	}
+	d.running[j.Alias] = true
+	d.mu.Unlock()
+
+	d.Logf("%s: next run at %s", j.Alias, formatTime(j.next))
+
+	d.wg.Add(1)
+	go func(alias string) {
+		defer d.wg.Done()
+
+		startedAt := time.Now()
+		d.Logf("%s: running", alias)
+		code := d.Run(alias)
+		d.Logf("%s: exited with code %d in %s", alias, code, time.Since(startedAt).Round(time.Millisecond))
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		d.running[alias] = false
+		d.state[stateKey(d.Dir, alias)] = startedAt
+		if err := saveState(d.statePath, stateKey(d.Dir, alias), startedAt); err != nil {
+			d.Logf("%s: failed to save schedule state: %v", alias, err)
+		}
+	}(j.Alias)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Format(time.DateTime)
+}
@@ -0,0 +1,231 @@
+package schedule_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/algrvvv/ali/schedule"
+)
+
+const daemonDir = "/home/user/project"
+
+// fakeRunner запоминает запущенные алиасы; пока открыт block, запуски не завершаются.
+type fakeRunner struct {
+	mu    sync.Mutex
+	runs  []string
+	logs  []string
+	block chan struct{}
+}
+
+func (r *fakeRunner) run(alias string) int {
+	r.mu.Lock()
+	r.runs = append(r.runs, alias)
+	block := r.block
+	r.mu.Unlock()
+
+	if block != nil {
+		<-block
+	}
+	return 0
+}
+
+func (r *fakeRunner) logf(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+func (r *fakeRunner) runCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.runs)
+}
+
+func (r *fakeRunner) logged(substr string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.ContainsFunc(r.logs, func(line string) bool { return strings.Contains(line, substr) })
+}
+
+// newDaemon создает демон с отдельным HOME; lastRun - время прошлого запуска backup, если не нулевое.
+func newDaemon(t *testing.T, lastRun time.Time) (*schedule.Daemon, *fakeRunner) {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	if !lastRun.IsZero() {
+		path, err := schedule.StatePath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := schedule.SaveState(path, schedule.StateKey(daemonDir, "backup"), lastRun); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runner := &fakeRunner{}
+	d, err := schedule.NewDaemon(daemonDir, runner.run, runner.logf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return d, runner
+}
+
+func backupJob(t *testing.T, spec, missed string) schedule.Job {
+	t.Helper()
+
+	cfg, err := schedule.ParseSpec(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Missed = missed
+	if err := cfg.Prepare(); err != nil {
+		t.Fatal(err)
+	}
+
+	return schedule.Job{Alias: "backup", Schedule: cfg}
+}
+
+func TestDaemonMissed(t *testing.T) {
+	now := time.Date(2024, 10, 12, 14, 0, 0, 0, time.UTC)
+	lastRun := now.Add(-3 * time.Hour)
+
+	tests := []struct {
+		missed   string
+		expected time.Time
+		runs     int
+	}{
+		{missed: schedule.MissedSkip, expected: now.Add(time.Hour), runs: 0},
+		{missed: schedule.MissedRun, expected: now, runs: 1},
+	}
+
+	for _, test := range tests {
+		d, runner := newDaemon(t, lastRun)
+		schedule.DaemonApply(d, []schedule.Job{backupJob(t, "1h", test.missed)}, now)
+
+		if next := schedule.DaemonNext(d, "backup"); !next.Equal(test.expected) {
+			t.Errorf("ERROR: missed %s: want: next run at %s; got: %s", test.missed, test.expected, next)
+		}
+
+		schedule.DaemonRunDue(d, now)
+		schedule.DaemonWait(d)
+
+		if runner.runCount() != test.runs {
+			t.Errorf("ERROR: missed %s: want: %d runs; got: %d", test.missed, test.runs, runner.runCount())
+		} else {
+			t.Logf("SUCCESS! missed %s: %d runs", test.missed, test.runs)
+		}
+	}
+}
+
+func TestDaemonLateRun(t *testing.T) {
+	now := time.Date(2024, 10, 12, 14, 0, 0, 0, time.UTC)
+
+	// демон проснулся сильно позже запланированного (компьютер спал)
+	d, runner := newDaemon(t, time.Time{})
+	schedule.DaemonApply(d, []schedule.Job{backupJob(t, "1h", schedule.MissedSkip)}, now)
+	schedule.DaemonRunDue(d, now.Add(3*time.Hour))
+	schedule.DaemonWait(d)
+
+	if runner.runCount() != 0 || !runner.logged("missed run") {
+		t.Errorf("ERROR: want: late run is skipped; got: %d runs, logs: %v", runner.runCount(), runner.logs)
+	}
+
+	if next := schedule.DaemonNext(d, "backup"); !next.Equal(now.Add(4 * time.Hour)) {
+		t.Errorf("ERROR: want: next run after wake up; got: %s", next)
+	} else {
+		t.Logf("SUCCESS! late run is skipped, next run at %s", next)
+	}
+}
+
+func TestDaemonStillRunning(t *testing.T) {
+	now := time.Date(2024, 10, 12, 14, 0, 0, 0, time.UTC)
+
+	d, runner := newDaemon(t, time.Time{})
+	runner.block = make(chan struct{})
+	schedule.DaemonApply(d, []schedule.Job{backupJob(t, "1m", schedule.MissedSkip)}, now)
+
+	schedule.DaemonRunDue(d, now.Add(time.Minute))
+	for runner.runCount() == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// следующий запуск наступил, а предыдущий еще работает
+	schedule.DaemonRunDue(d, now.Add(2*time.Minute))
+	close(runner.block)
+	schedule.DaemonWait(d)
+
+	if runner.runCount() != 1 || !runner.logged("previous run is still running") {
+		t.Errorf("ERROR: want: one run; got: %d runs, logs: %v", runner.runCount(), runner.logs)
+	} else {
+		t.Logf("SUCCESS! run is skipped while previous one is running")
+	}
+
+	// после завершения время запуска сохранено для следующего старта демона
+	path, _ := schedule.StatePath()
+	state, err := schedule.LoadState(path)
+	if _, ok := state[schedule.StateKey(daemonDir, "backup")]; err != nil || !ok {
+		t.Errorf("ERROR: want: last run in state; got: %v (%v)", state, err)
+	}
+}
+
+func TestDaemonReload(t *testing.T) {
+	now := time.Date(2024, 10, 12, 14, 0, 0, 0, time.UTC)
+
+	d, runner := newDaemon(t, time.Time{})
+	schedule.DaemonApply(d, []schedule.Job{backupJob(t, "1h", schedule.MissedSkip)}, now)
+
+	// то же расписание после перезагрузки конфига - время не пересчитывается
+	schedule.DaemonApply(d, []schedule.Job{backupJob(t, "1h", schedule.MissedSkip)}, now.Add(30*time.Minute))
+	if next := schedule.DaemonNext(d, "backup"); !next.Equal(now.Add(time.Hour)) {
+		t.Errorf("ERROR: unchanged schedule: want: %s; got: %s", now.Add(time.Hour), next)
+	}
+
+	// изменилось расписание - время считается заново
+	schedule.DaemonApply(d, []schedule.Job{backupJob(t, "2h", schedule.MissedSkip)}, now.Add(30*time.Minute))
+	if next := schedule.DaemonNext(d, "backup"); !next.Equal(now.Add(150 * time.Minute)) {
+		t.Errorf("ERROR: changed schedule: want: %s; got: %s", now.Add(150*time.Minute), next)
+	}
+
+	schedule.DaemonApply(d, nil, now.Add(time.Hour))
+	if !schedule.DaemonNext(d, "backup").IsZero() || !runner.logged("backup: removed from schedule") {
+		t.Errorf("ERROR: want: backup is removed from schedule; logs: %v", runner.logs)
+	} else {
+		t.Logf("SUCCESS! schedule is kept and updated on reload")
+	}
+}
+
+func TestDaemonLoop(t *testing.T) {
+	// пропущенный запуск с missed: run выполняется сразу после SetJobs
+	d, runner := newDaemon(t, time.Now().Add(-3*time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Loop(ctx)
+		close(done)
+	}()
+
+	d.SetJobs([]schedule.Job{backupJob(t, "1h", schedule.MissedRun)})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for runner.runCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if runner.runCount() != 1 {
+		t.Errorf("ERROR: want: one run; got: %d, logs: %v", runner.runCount(), runner.logs)
+	} else {
+		t.Logf("SUCCESS! missed run is executed by loop")
+	}
+}
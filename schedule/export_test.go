@@ -0,0 +1,25 @@
+package schedule
+
+import "time"
+
+// внутренние функции пакета для внешних тестов
+var (
+	SaveState = saveState
+	LoadState = loadState
+	StateKey  = stateKey
+)
+
+func DaemonApply(d *Daemon, jobs []Job, now time.Time) { d.apply(jobs, now) }
+
+func DaemonRunDue(d *Daemon, now time.Time) { d.runDue(now) }
+
+// DaemonWait ждет завершения запущенных алиасов.
+func DaemonWait(d *Daemon) { d.wg.Wait() }
+
+// DaemonNext время следующего запуска алиаса, нулевое если его нет в расписании.
+func DaemonNext(d *Daemon, alias string) time.Time {
+	if j, ok := d.jobs[alias]; ok {
+		return j.next
+	}
+	return time.Time{}
+}
@@ -0,0 +1,49 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/algrvvv/ali/schedule"
+)
+
+func TestCronNext(t *testing.T) {
+	// суббота
+	from := time.Date(2024, 10, 12, 14, 37, 20, 0, time.UTC)
+
+	tests := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{spec: "* * * * *", expected: time.Date(2024, 10, 12, 14, 38, 0, 0, time.UTC)},
+		{spec: "*/15 * * * *", expected: time.Date(2024, 10, 12, 14, 45, 0, 0, time.UTC)},
+		{spec: "0 3 * * *", expected: time.Date(2024, 10, 13, 3, 0, 0, 0, time.UTC)},
+		{spec: "@hourly", expected: time.Date(2024, 10, 12, 15, 0, 0, 0, time.UTC)},
+		{spec: "30 9 * * mon-fri", expected: time.Date(2024, 10, 14, 9, 30, 0, 0, time.UTC)},
+		{spec: "0 0 1 jan *", expected: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 12 13 * 1", expected: time.Date(2024, 10, 13, 12, 0, 0, 0, time.UTC)},
+		{spec: "0 0 * * 7", expected: time.Date(2024, 10, 13, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 30 2 *", expected: time.Time{}},
+	}
+
+	for _, test := range tests {
+		cron, err := schedule.ParseCron(test.spec)
+		if err != nil {
+			t.Errorf("ERROR: %s: %v", test.spec, err)
+			continue
+		}
+
+		got := cron.Next(from)
+		if !got.Equal(test.expected) {
+			t.Errorf("ERROR: %s: want: %s; got: %s", test.spec, test.expected, got)
+		} else {
+			t.Logf("SUCCESS! %s: got: %s", test.spec, got)
+		}
+	}
+
+	for _, spec := range []string{"* * * *", "60 * * * *", "* * * * mon-xyz", "*/0 * * * *"} {
+		if _, err := schedule.ParseCron(spec); err == nil {
+			t.Errorf("ERROR: %s: want error", spec)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/algrvvv/ali/filelock"
+)
+
+// State время последнего запуска каждого алиаса по расписанию.
+// Ключ - директория демона и имя алиаса, см. stateKey.
+type State map[string]time.Time
+
+// StatePath путь до файла состояния: ~/.ali/schedule.json.
+func StatePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".ali", "schedule.json"), nil
+}
+
+func stateKey(dir, alias string) string {
+	return dir + ":" + alias
+}
+
+func loadState(path string) (State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return State{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	state := State{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// saveState перечитывает файл перед записью: его могут менять демоны из других директорий.
+// Чтение и запись идут под блокировкой, иначе демоны теряют время запусков друг друга.
+func saveState(path, key string, lastRun time.Time) error {
+	unlock, err := filelock.LockPath(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := loadState(path)
+	if err != nil {
+		state = State{}
+	}
+	state[key] = lastRun
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return filelock.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,52 @@
+package schedule_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/algrvvv/ali/schedule"
+)
+
+// запускается с GOMAXPROCS > 1: без блокировки демоны теряют записи друг друга
+func TestSaveStateConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "schedule.json")
+	lastRun := time.Date(2024, 10, 12, 14, 0, 0, 0, time.UTC)
+
+	const daemons = 50
+
+	var wg sync.WaitGroup
+	for i := 0; i < daemons; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := schedule.SaveState(path, fmt.Sprintf("/project%d:backup", i), lastRun); err != nil {
+				t.Errorf("ERROR: save state: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	state, err := schedule.LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(state) != daemons {
+		t.Errorf("ERROR: want: %d last runs; got: %d", daemons, len(state))
+	} else {
+		t.Logf("SUCCESS! %d daemons saved their last runs", daemons)
+	}
+
+	// временные файлы не остаются
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if name := entry.Name(); name != "schedule.json" && name != "schedule.json.lock" {
+			t.Errorf("ERROR: unexpected file: %s", name)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/algrvvv/ali/parallel"
+	"github.com/algrvvv/ali/utils"
+)
+
+// Alias описание алиаса из конфигурации.
+type Alias = utils.AliasEntry
+
+var ErrAliasNotFound = errors.New("alias not found")
+
+// Config загруженная конфигурация ali.
+type Config struct {
+	// GlobalFile путь до глобального конфига, пусто если он не загружался
+	GlobalFile string
+	// LocalFiles загруженные локальные конфиги, ближайший - первый
+	LocalFiles []string
+	// IncludedFiles подключенные через include конфиги в порядке загрузки
+	IncludedFiles []string
+	// IncludeErrors ошибки подключения конфигов
+	IncludeErrors []error
+	// UntrustedFiles локальные и подключенные конфиги, пропущенные по Options.Trusted
+	UntrustedFiles []string
+	// Profile выбранный профиль vars и env, пусто без профиля
+	Profile string
+	// Strict не собирать команды с неразрешенными {{var}}, <param> и $ENV
+	Strict bool
+
+	v *viper.Viper
+}
+
+// Files все загруженные конфиги в порядке загрузки.
+func (c *Config) Files() []string {
+	return append(c.baseFiles(), c.IncludedFiles...)
+}
+
+// baseFiles глобальный и локальные конфиги в порядке загрузки, без подключенных.
+func (c *Config) baseFiles() []string {
+	var files []string
+	if c.GlobalFile != "" {
+		files = append(files, c.GlobalFile)
+	}
+	for i := len(c.LocalFiles) - 1; i >= 0; i-- {
+		files = append(files, c.LocalFiles[i])
+	}
+
+	return files
+}
+
+// Settings итоговые настройки после слияния всех конфигов.
+func (c *Config) Settings() map[string]any {
+	return c.v.AllSettings()
+}
+
+// Vars переменные из секции vars.
+func (c *Config) Vars() (map[string]string, error) {
+	var vars struct {
+		Vars map[string]string `mapstructure:"vars"`
+	}
+	if err := c.v.Unmarshal(&vars); err != nil {
+		return nil, err
+	}
+
+	return vars.Vars, nil
+}
+
+// Env переменные окружения алиаса: глобальные из env и заданные в самом алиасе.
+func (c *Config) Env(alias *Alias) map[string]any {
+	// NOTE: viper отдает свою карту, поэтому копируем
+	env := maps.Clone(c.v.GetStringMap("env"))
+	maps.Copy(env, alias.Env)
+
+	return env
+}
+
+// Aliases все алиасы конфигурации, включая описанные только в секции parallel.
+// Алиасы, которые не удалось разобрать, пропускаются, а их ошибки возвращаются вместе.
+func (c *Config) Aliases() (map[string]Alias, error) {
+	aliases, err := utils.LoadAliases(c.v)
+
+	for name := range c.v.GetStringMap(parallel.ParallelPrefix) {
+		if _, ok := aliases[name]; !ok {
+			aliases[name] = Alias{AliasName: name, Parallel: true}
+		}
+	}
+
+	return aliases, err
+}
+
+// Groups группы алиасов, ключ - полное имя группы: db или db:local.
+func (c *Config) Groups() map[string]utils.AliasGroup {
+	return utils.LoadGroups(c.v)
+}
+
+// Lookup находит имя алиаса по аргументам командной строки: ali db migrate
+// запускает алиас db:migrate группы db. Возвращает имя и оставшиеся аргументы.
+func (c *Config) Lookup(args []string) (string, []string) {
+	if len(args) == 0 {
+		return "", nil
+	}
+
+	aliases, _ := c.Aliases()
+	groups := c.Groups()
+
+	name, rest := args[0], args[1:]
+	for len(rest) > 0 {
+		if _, ok := groups[name]; !ok {
+			break
+		}
+
+		next := name + utils.GroupSeparator + rest[0]
+		_, isAlias := aliases[next]
+		_, isGroup := groups[next]
+		if !isAlias && !isGroup {
+			break
+		}
+
+		name, rest = next, rest[1:]
+	}
+
+	return name, rest
+}
+
+// Alias ищет алиас по имени или синониму.
+func (c *Config) Alias(name string) (*Alias, error) {
+	aliases, err := c.Aliases()
+
+	// NOTE: ошибки других алиасов не мешают запустить рабочий
+	if entry := utils.SearchSynonyms(aliases, name); entry != nil {
+		return entry, nil
+	}
+
+	if group, ok := c.Groups()[name]; ok {
+		return nil, &GroupError{Group: group, Aliases: groupAliases(aliases, name)}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s (%w)", ErrAliasNotFound, name, err)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrAliasNotFound, name)
+}
+
+// GroupError вместо алиаса указана группа.
+type GroupError struct {
+	Group utils.AliasGroup
+	// Aliases полные имена алиасов группы
+	Aliases []string
+}
+
+func (e *GroupError) Error() string {
+	return fmt.Sprintf("%q is a group of aliases: %s", e.Group.Name, strings.Join(e.Aliases, ", "))
+}
+
+func groupAliases(aliases map[string]Alias, group string) []string {
+	var names []string
+	for name := range aliases {
+		if strings.HasPrefix(name, group+utils.GroupSeparator) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	return names
+}
@@ -0,0 +1,210 @@
+// Package sdk позволяет использовать алиасы ali из других программ на Go:
+// загрузить конфиги так же, как это делает ali, собрать план выполнения алиаса
+// и запустить его со своими вводом, выводом и обработчиком событий.
+//
+//	cfg, err := sdk.Load(sdk.Options{Dir: "."})
+//	plan, err := cfg.Resolve("build", []string{"--target=prod"})
+//	code, err := (&sdk.Runner{Stdout: os.Stdout, Stderr: os.Stderr}).Run(ctx, plan)
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/algrvvv/ali/logger"
+	"github.com/algrvvv/ali/utils"
+)
+
+// LocalConfigName имя локального конфига.
+const LocalConfigName = ".ali"
+
+var ErrProfileNotFound = errors.New("profile not found")
+
+// Options настройки загрузки конфигурации.
+type Options struct {
+	// Dir директория, от которой ищутся локальные конфиги; по умолчанию текущая
+	Dir string
+	// GlobalConfig путь до глобального конфига; по умолчанию ~/.ali/config.yml
+	GlobalConfig string
+	// NoGlobal не загружать глобальный конфиг, как ali -L
+	NoGlobal bool
+	// Profile профиль vars и env; по умолчанию из ALI_PROFILE или app.profile
+	Profile string
+	// NoProfile не применять профиль
+	NoProfile bool
+	// Trusted проверяет локальные и подключенные конфиги перед загрузкой:
+	// недоверенные пропускаются и попадают в Config.UntrustedFiles. Nil - доверять всем
+	Trusted func(path string) bool
+}
+
+// Load загружает конфигурацию так же, как ali: глобальный конфиг, локальные .ali
+// от дальнего к ближнему и подключенные через include конфиги.
+// Ошибки подключения конфигов не прерывают загрузку и сохраняются в Config.IncludeErrors.
+func Load(opts Options) (*Config, error) {
+	v := viper.New()
+	v.AutomaticEnv()
+
+	cfg := &Config{v: v}
+
+	if !opts.NoGlobal {
+		global, err := readGlobalConfig(v, opts.GlobalConfig)
+		if err != nil {
+			return nil, err
+		}
+		cfg.GlobalFile = global
+		logger.SaveDebugf("using config: %s", global)
+	}
+
+	dir := opts.Dir
+	if dir == "" {
+		dir = "."
+	}
+
+	files, err := utils.FindLocalConfigs(dir, LocalConfigName, opts.Trusted)
+	if err != nil {
+		logger.SaveDebugf("failed to find local configs: %v", err)
+	}
+	logger.SaveDebugf("found local configs: %v", files)
+
+	// NOTE: include недоверенного конфига тоже не подключаются, поэтому проверяем до ResolveIncludes
+	files = cfg.trusted(opts, files)
+	cfg.LocalFiles = files
+
+	// NOTE: сливаем от дальнего конфига к ближнему, чтобы ближние перекрывали дальние
+	for i := len(files) - 1; i >= 0; i-- {
+		utils.UseConfigFile(v, files[i])
+		if err := v.MergeInConfig(); err != nil {
+			logger.SaveDebugf("load local config %s error: %v", files[i], err)
+			continue
+		}
+		logger.SaveDebugf("local config loaded: %s", files[i])
+	}
+
+	includes, errs := utils.ResolveIncludes(cfg.baseFiles(), opts.Trusted)
+	cfg.IncludeErrors = errs
+	logger.SaveDebugf("includes: %v", includes)
+
+	for _, path := range cfg.trusted(opts, includes) {
+		utils.UseConfigFile(v, path)
+		if err := v.MergeInConfig(); err != nil {
+			cfg.IncludeErrors = append(cfg.IncludeErrors, fmt.Errorf("failed to load %s: %w", path, err))
+			continue
+		}
+
+		logger.SaveDebugf("included config loaded: %s", path)
+		cfg.IncludedFiles = append(cfg.IncludedFiles, path)
+	}
+
+	if !opts.NoProfile {
+		if err := cfg.applyProfile(opts.Profile); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg.Strict = v.GetBool(utils.StrictKey)
+
+	return cfg, nil
+}
+
+// trusted оставляет доверенные конфиги, остальные запоминает в UntrustedFiles.
+func (c *Config) trusted(opts Options, files []string) []string {
+	if opts.Trusted == nil {
+		return files
+	}
+
+	var out []string
+	for _, file := range files {
+		if !opts.Trusted(file) {
+			logger.SaveDebugf("untrusted config skipped: %s", file)
+			c.UntrustedFiles = append(c.UntrustedFiles, file)
+			continue
+		}
+		out = append(out, file)
+	}
+
+	return out
+}
+
+// applyProfile накладывает vars и env выбранного профиля поверх vars и env конфигов.
+func (c *Config) applyProfile(name string) error {
+	if name == "" {
+		name = os.Getenv(utils.ProfileEnv)
+	}
+	if name == "" {
+		name = c.v.GetString(utils.DefaultProfileKey)
+	}
+	if name == "" {
+		return nil
+	}
+
+	profiles := c.v.GetStringMap(utils.ProfilesKey)
+	if _, ok := profiles[strings.ToLower(name)]; !ok {
+		names := slices.Sorted(maps.Keys(profiles))
+		return fmt.Errorf("%w: %q (available: %s)", ErrProfileNotFound, name, strings.Join(names, ", "))
+	}
+
+	var profile utils.Profile
+	if err := c.v.UnmarshalKey(utils.ProfilesKey+"."+name, &profile); err != nil {
+		return fmt.Errorf("failed to get profile %q: %w", name, err)
+	}
+
+	// NOTE: слияние вложенное: переопределяются только заданные в профиле ключи
+	err := c.v.MergeConfigMap(map[string]any{
+		"vars": toAnyMap(profile.Vars),
+		"env":  profile.Env,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Profile = strings.ToLower(name)
+	logger.SaveDebugf("using profile: %s", c.Profile)
+
+	return nil
+}
+
+func toAnyMap(m map[string]string) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+
+	return out
+}
+
+// readGlobalConfig читает глобальный конфиг и возвращает путь до него.
+// По умолчанию ищется ~/.ali/config.yml, config.yaml, config.toml или config.json.
+func readGlobalConfig(v *viper.Viper, path string) (string, error) {
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		dir := filepath.Join(home, ".ali")
+		// NOTE: у глобального конфига расширение обязательно, ~/.ali/config не ищется
+		for _, name := range utils.ConfigFileNames("config")[1:] {
+			if utils.FileExists(filepath.Join(dir, name)) {
+				path = filepath.Join(dir, name)
+				break
+			}
+		}
+		if path == "" {
+			return "", fmt.Errorf("global config not found in %s (use ali setup)", dir)
+		}
+	}
+
+	utils.UseConfigFile(v, path)
+	if err := v.ReadInConfig(); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
@@ -0,0 +1,60 @@
+package sdk
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// prefixWriter дописывает [label] в начало каждой строки вывода.
+// Строки пишутся целиком под общим мьютексом, чтобы вывод команд не перемешивался.
+type prefixWriter struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	prefix []byte
+	buf    []byte
+}
+
+func newPrefixWriter(w io.Writer, mu *sync.Mutex, label string) *prefixWriter {
+	return &prefixWriter{w: w, mu: mu, prefix: []byte("[" + label + "] ")}
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		if err := p.writeLine(p.buf[:i+1]); err != nil {
+			return len(b), err
+		}
+		p.buf = p.buf[i+1:]
+	}
+
+	return len(b), nil
+}
+
+// Flush выводит последнюю строку без перевода строки.
+func (p *prefixWriter) Flush() {
+	if len(p.buf) == 0 {
+		return
+	}
+
+	_ = p.writeLine(append(p.buf, '\n'))
+	p.buf = nil
+}
+
+func (p *prefixWriter) writeLine(line []byte) error {
+	if p.w == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err := p.w.Write(append(append([]byte{}, p.prefix...), line...))
+	return err
+}
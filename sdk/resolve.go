@@ -0,0 +1,184 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/algrvvv/ali/parallel"
+	"github.com/algrvvv/ali/utils"
+)
+
+// ErrUnsupported алиас использует возможности параллельного запуска, которые есть только в cli.
+var ErrUnsupported = errors.New("not supported by sdk")
+
+// Plan что будет выполнено при запуске алиаса.
+type Plan struct {
+	Alias Alias
+	// Parallel команды выполняются одновременно, иначе - по очереди до первой ошибки
+	Parallel bool
+	Steps    []Step
+}
+
+// Step одна команда плана.
+type Step struct {
+	Label string
+	// Command команда после подстановки аргументов, флагов и переменных
+	Command string
+	Dir     string
+	Env     map[string]any
+	// Host хост для запуска по ssh, пусто для локального запуска
+	Host string
+	// TTY команде нужен терминал
+	TTY bool
+}
+
+// Resolve собирает план алиаса по аргументам командной строки:
+// аргументы, начинающиеся с "-", считаются флагами (--name=value), остальные - параметрами.
+// После "--" все аргументы считаются параметрами.
+func (c *Config) Resolve(alias string, args []string) (*Plan, error) {
+	params, flags := ParseArgs(args)
+	return c.ResolveWith(alias, params, flags)
+}
+
+// ResolveWith собирает план алиаса по уже разобранным параметрам и флагам.
+func (c *Config) ResolveWith(alias string, params []string, flags map[string]string) (*Plan, error) {
+	entry, err := c.Alias(alias)
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := c.Invocation(entry, params, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Alias: *entry, Parallel: entry.Parallel}
+
+	if entry.Parallel {
+		commands, err := c.ParallelCommands(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkParallel(entry, commands); err != nil {
+			return nil, err
+		}
+
+		for _, command := range commands {
+			plan.Steps = append(plan.Steps, Step{
+				Label:   command.Label,
+				Command: command.Command,
+				Dir:     command.Path,
+				Env:     inv.Env(),
+				Host:    command.Host,
+				TTY:     command.TTY,
+			})
+		}
+	} else {
+		for i, command := range entry.Cmds {
+			label := entry.AliasName
+			if len(entry.Cmds) > 1 {
+				label = fmt.Sprintf("%s#%d", entry.AliasName, i+1)
+			}
+
+			plan.Steps = append(plan.Steps, Step{
+				Label:   label,
+				Command: command,
+				Dir:     entry.Dir,
+				Env:     inv.Env(),
+				Host:    entry.Host,
+				TTY:     entry.TTY,
+			})
+		}
+	}
+
+	for i := range plan.Steps {
+		step := &plan.Steps[i]
+
+		_, local := utils.NewExecutor(step.Host, false).(utils.LocalExecutor)
+		step.Command, err = inv.Build(step.Command, local)
+		if err != nil {
+			return nil, fmt.Errorf("[%s]: %w", step.Label, err)
+		}
+	}
+
+	return plan, nil
+}
+
+// Invocation контекст запуска алиаса с переменными и окружением конфигурации.
+func (c *Config) Invocation(entry *Alias, params []string, flags map[string]string) (*utils.Invocation, error) {
+	vars, err := c.Vars()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vars: %w", err)
+	}
+
+	return utils.NewInvocation(utils.InvocationOptions{
+		Vars:   vars,
+		Env:    c.Env(entry),
+		Params: params,
+		Flags:  flags,
+		Dir:    entry.Dir,
+		Strict: c.Strict,
+	}), nil
+}
+
+// ParallelCommands команды параллельного алиаса.
+func (c *Config) ParallelCommands(entry *Alias) ([]parallel.Command, error) {
+	return parallel.GetCommands(c.v, entry)
+}
+
+// checkParallel не дает молча выполнить алиас иначе, чем в cli: Runner запускает
+// все команды сразу и не умеет ждать готовности, перезапускать и группировать вывод.
+func checkParallel(entry *Alias, commands []parallel.Command) error {
+	var unsupported []string
+	if entry.OutputMode == parallel.OutputGrouped {
+		unsupported = append(unsupported, "output: "+parallel.OutputGrouped)
+	}
+	if entry.UI != "" {
+		unsupported = append(unsupported, "ui")
+	}
+
+	for _, command := range commands {
+		if len(command.StartAfter) > 0 {
+			unsupported = append(unsupported, fmt.Sprintf("[%s] start_after", command.Label))
+		}
+		if command.ReadyWhen != nil {
+			unsupported = append(unsupported, fmt.Sprintf("[%s] ready_when", command.Label))
+		}
+		switch command.Restart {
+		case "", "0", "false", parallel.RestartNo:
+		default:
+			unsupported = append(unsupported, fmt.Sprintf("[%s] restart", command.Label))
+		}
+	}
+
+	if len(unsupported) > 0 {
+		return fmt.Errorf("alias %q: %w: %s", entry.AliasName, ErrUnsupported, strings.Join(unsupported, ", "))
+	}
+
+	return nil
+}
+
+// ParseArgs разделяет аргументы на параметры и флаги так же, как ali.
+func ParseArgs(args []string) ([]string, map[string]string) {
+	var params []string
+	flags := make(map[string]string)
+
+	for i, arg := range args {
+		if arg == "--" {
+			params = append(params, args[i+1:]...)
+			break
+		}
+
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			params = append(params, arg)
+			continue
+		}
+
+		name, value, _ := strings.Cut(arg, "=")
+		flags[name] = value
+	}
+
+	return params, flags
+}
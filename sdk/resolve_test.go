@@ -0,0 +1,291 @@
+package sdk_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/algrvvv/ali/sdk"
+)
+
+func writeConfigs(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestResolve(t *testing.T) {
+	dir := writeConfigs(t, map[string]string{
+		"config.yml": strings.Join([]string{
+			"vars:",
+			"  target: dev",
+			"env:",
+			"  mode: global",
+			"aliases:",
+			"  build:",
+			"    aliases: [b]",
+			"    dir: /tmp",
+			"    env:",
+			"      mode: build",
+			"    cmds:",
+			"      - go build -o {{target}} <out>",
+			"      - echo done",
+			"",
+		}, "\n"),
+		"project/.ali": strings.Join([]string{
+			"root: true",
+			"include: [shared.yml]",
+			"vars:",
+			"  target: prod",
+			"",
+		}, "\n"),
+		"project/shared.yml": "parallel:\n  dev:\n    - command: npm run dev\n      label: web\n",
+	})
+
+	cfg, err := sdk.Load(sdk.Options{
+		Dir:          filepath.Join(dir, "project"),
+		GlobalConfig: filepath.Join(dir, "config.yml"),
+	})
+	if err != nil {
+		t.Fatalf("ERROR: failed to load config: %v", err)
+	}
+
+	if len(cfg.Files()) != 3 || len(cfg.IncludeErrors) != 0 {
+		t.Fatalf("ERROR: want: 3 config files; got: %v (%v)", cfg.Files(), cfg.IncludeErrors)
+	}
+
+	plan, err := cfg.Resolve("b", []string{"--out=bin", "--verbose", "./cmd"})
+	if err != nil {
+		t.Fatalf("ERROR: failed to resolve alias: %v", err)
+	}
+
+	expected := []string{"go build -o prod bin --verbose ./cmd", "echo done --out=bin --verbose ./cmd"}
+	if plan.Parallel || len(plan.Steps) != len(expected) {
+		t.Fatalf("ERROR: want: %d sequential steps; got: %+v", len(expected), plan)
+	}
+
+	for i, step := range plan.Steps {
+		if step.Command != expected[i] || step.Dir != "/tmp" || step.Env["mode"] != "build" {
+			t.Errorf("ERROR: want: %s in /tmp with mode=build; got: %+v", expected[i], step)
+		} else {
+			t.Logf("SUCCESS! got: %s", step.Command)
+		}
+	}
+
+	plan, err = cfg.Resolve("dev", nil)
+	if err != nil {
+		t.Fatalf("ERROR: failed to resolve parallel alias: %v", err)
+	}
+
+	if !plan.Parallel || len(plan.Steps) != 1 || plan.Steps[0].Label != "web" || plan.Steps[0].Env["mode"] != "global" {
+		t.Errorf("ERROR: want: parallel step web with mode=global; got: %+v", plan)
+	} else {
+		t.Logf("SUCCESS! got parallel step: %+v", plan.Steps[0])
+	}
+
+	if _, err := cfg.Resolve("missing", nil); err == nil {
+		t.Errorf("ERROR: want: alias not found error")
+	}
+}
+
+func TestResolveStrict(t *testing.T) {
+	dir := writeConfigs(t, map[string]string{
+		".ali": "root: true\nstrict: true\naliases:\n  deploy: ./deploy.sh {{stage}} <host>\n",
+	})
+
+	cfg, err := sdk.Load(sdk.Options{Dir: dir, NoGlobal: true})
+	if err != nil {
+		t.Fatalf("ERROR: failed to load config: %v", err)
+	}
+
+	if _, err := cfg.Resolve("deploy", nil); err == nil {
+		t.Errorf("ERROR: want: error for unresolved references")
+	} else {
+		t.Logf("SUCCESS! got: %v", err)
+	}
+
+	if _, err := cfg.Resolve("deploy", []string{"-V_stage=prod", "--host=web"}); err != nil {
+		t.Errorf("ERROR: want: no error; got: %v", err)
+	}
+}
+
+func TestResolveUnsupported(t *testing.T) {
+	dir := writeConfigs(t, map[string]string{
+		".ali": `root: true
+aliases:
+  dev: {parallel: true}
+  logs: {parallel: true, output: grouped}
+  plain: {parallel: true, timestamps: wall}
+parallel:
+  dev:
+    - {label: db, command: ./db.sh, restart: on-failure}
+    - {label: api, command: ./api.sh, start_after: [db]}
+  logs:
+    - {command: tail -f a.log}
+  plain:
+    - {command: echo a, restart: "no"}
+`,
+	})
+
+	cfg, err := sdk.Load(sdk.Options{Dir: dir, NoGlobal: true})
+	if err != nil {
+		t.Fatalf("ERROR: failed to load config: %v", err)
+	}
+
+	for _, alias := range []string{"dev", "logs"} {
+		if _, err := cfg.Resolve(alias, nil); !errors.Is(err, sdk.ErrUnsupported) {
+			t.Errorf("ERROR: %s: want: ErrUnsupported; got: %v", alias, err)
+		} else {
+			t.Logf("SUCCESS! %s: got: %v", alias, err)
+		}
+	}
+
+	if _, err := cfg.Resolve("plain", nil); err != nil {
+		t.Errorf("ERROR: plain: want: no error; got: %v", err)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	dir := writeConfigs(t, map[string]string{
+		".ali": "root: true\naliases:\n  db:\n    migrate: migrate <n>\n    local:\n      reset: reset\n  build: go build\n",
+	})
+
+	cfg, err := sdk.Load(sdk.Options{Dir: dir, NoGlobal: true})
+	if err != nil {
+		t.Fatalf("ERROR: failed to load config: %v", err)
+	}
+
+	tests := []struct {
+		args  []string
+		alias string
+		rest  []string
+	}{
+		{args: []string{"db", "migrate", "--n=1"}, alias: "db:migrate", rest: []string{"--n=1"}},
+		{args: []string{"db:migrate"}, alias: "db:migrate"},
+		{args: []string{"db", "local", "reset", "x"}, alias: "db:local:reset", rest: []string{"x"}},
+		{args: []string{"db", "nope"}, alias: "db", rest: []string{"nope"}},
+		{args: []string{"build", "migrate"}, alias: "build", rest: []string{"migrate"}},
+	}
+
+	for _, test := range tests {
+		alias, rest := cfg.Lookup(test.args)
+		if alias != test.alias || strings.Join(rest, " ") != strings.Join(test.rest, " ") {
+			t.Errorf("ERROR: %v: want: %s %v; got: %s %v", test.args, test.alias, test.rest, alias, rest)
+		} else {
+			t.Logf("SUCCESS! %v -> %s %v", test.args, alias, rest)
+		}
+	}
+
+	var groupErr *sdk.GroupError
+	if _, err := cfg.Alias("db"); !errors.As(err, &groupErr) || len(groupErr.Aliases) != 2 {
+		t.Errorf("ERROR: want: group error with 2 aliases; got: %v", err)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	dir := writeConfigs(t, map[string]string{
+		".ali": strings.Join([]string{
+			"root: true",
+			"app:",
+			"  profile: stage",
+			"vars:",
+			"  api: localhost",
+			"  user: me",
+			"env:",
+			"  token: dev",
+			"profiles:",
+			"  prod:",
+			"    vars: {api: api.example.com}",
+			"    env: {token: prod}",
+			"  stage:",
+			"    vars: {api: stage.example.com}",
+			"aliases:",
+			"  deploy: deploy {{api}} {{user}}",
+			"",
+		}, "\n"),
+	})
+
+	tests := []struct {
+		name    string
+		profile string
+		env     string
+		command string
+		token   string
+	}{
+		{name: "default from app.profile", command: "deploy stage.example.com me", token: "dev"},
+		{name: "ALI_PROFILE", env: "prod", command: "deploy api.example.com me", token: "prod"},
+		{name: "option wins", profile: "stage", env: "prod", command: "deploy stage.example.com me", token: "dev"},
+	}
+
+	for _, test := range tests {
+		t.Setenv("ALI_PROFILE", test.env)
+
+		cfg, err := sdk.Load(sdk.Options{Dir: dir, NoGlobal: true, Profile: test.profile})
+		if err != nil {
+			t.Fatalf("ERROR: %s: failed to load config: %v", test.name, err)
+		}
+
+		plan, err := cfg.Resolve("deploy", nil)
+		if err != nil {
+			t.Fatalf("ERROR: %s: failed to resolve alias: %v", test.name, err)
+		}
+
+		step := plan.Steps[0]
+		if strings.TrimSpace(step.Command) != test.command || step.Env["token"] != test.token {
+			t.Errorf("ERROR: %s: want: %s with token %s; got: %s with token %v", test.name, test.command, test.token, step.Command, step.Env["token"])
+		} else {
+			t.Logf("SUCCESS! %s: %s (profile %s)", test.name, step.Command, cfg.Profile)
+		}
+	}
+
+	if _, err := sdk.Load(sdk.Options{Dir: dir, NoGlobal: true, Profile: "nope"}); !errors.Is(err, sdk.ErrProfileNotFound) {
+		t.Errorf("ERROR: want: profile not found error; got: %v", err)
+	}
+}
+
+func TestLoadTrusted(t *testing.T) {
+	dir := writeConfigs(t, map[string]string{
+		"project/.ali":       "include: [shared.yml]\naliases:\n  build: make\n",
+		"project/shared.yml": "aliases:\n  deploy: ./deploy.sh\n",
+		"project/src/.ali":   "aliases:\n  test: go test ./...\n",
+	})
+	untrusted := filepath.Join(dir, "project", ".ali")
+
+	cfg, err := sdk.Load(sdk.Options{
+		Dir:      filepath.Join(dir, "project", "src"),
+		NoGlobal: true,
+		Trusted:  func(path string) bool { return path != untrusted },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// include недоверенного конфига тоже не подключается
+	for _, name := range []string{"build", "deploy"} {
+		if _, err := cfg.Alias(name); !errors.Is(err, sdk.ErrAliasNotFound) {
+			t.Errorf("ERROR: alias %q of untrusted config is loaded", name)
+		}
+	}
+	if _, err := cfg.Alias("test"); err != nil {
+		t.Errorf("ERROR: alias of trusted config: %v", err)
+	}
+
+	if len(cfg.UntrustedFiles) != 1 || cfg.UntrustedFiles[0] != untrusted {
+		t.Errorf("ERROR: want untrusted: [%s]; got: %v", untrusted, cfg.UntrustedFiles)
+	} else {
+		t.Logf("SUCCESS! untrusted: %v", cfg.UntrustedFiles)
+	}
+}
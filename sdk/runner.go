@@ -0,0 +1,177 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+// EventKind тип события выполнения плана.
+type EventKind string
+
+const (
+	// EventStart команда запускается
+	EventStart EventKind = "start"
+	// EventExit команда завершилась или не смогла запуститься
+	EventExit EventKind = "exit"
+)
+
+const defaultKillDelay = 5 * time.Second
+
+// Event событие выполнения одной команды плана.
+type Event struct {
+	Kind EventKind
+	Step Step
+	Time time.Time
+	// ExitCode код завершения для EventExit, -1 если команда не запустилась
+	ExitCode int
+	// Err ошибка запуска или завершения для EventExit
+	Err error
+}
+
+// Runner выполняет планы алиасов.
+type Runner struct {
+	// Stdin ввод команд; у параллельных команд ввода нет. nil - пустой ввод
+	Stdin io.Reader
+	// Stdout и Stderr вывод команд; nil - вывод отбрасывается
+	Stdout io.Writer
+	Stderr io.Writer
+	// TTY запрашивать терминал для команд на удаленном хосте
+	TTY bool
+	// KillDelay сколько ждать завершения команды после прерывания, прежде чем завершить ее принудительно;
+	// по умолчанию 5 секунд
+	KillDelay time.Duration
+	// OnEvent вызывается при запуске и завершении каждой команды;
+	// для параллельного плана - из разных горутин
+	OnEvent func(Event)
+}
+
+// Run выполняет план и возвращает код завершения.
+// Команды обычного алиаса выполняются по очереди до первой ошибки,
+// команды параллельного - одновременно, а их вывод помечается префиксом [label].
+// При отмене ctx командам отправляется сигнал прерывания.
+func (r *Runner) Run(ctx context.Context, plan *Plan) (int, error) {
+	if plan.Parallel {
+		return r.runParallel(ctx, plan.Steps)
+	}
+
+	for _, step := range plan.Steps {
+		code, err := r.runStep(ctx, step, r.Stdin, r.Stdout, r.Stderr, r.TTY)
+		if err != nil {
+			return code, err
+		}
+	}
+
+	return 0, nil
+}
+
+func (r *Runner) runParallel(ctx context.Context, steps []Step) (int, error) {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		codes = make([]int, len(steps))
+		errs  = make([]error, len(steps))
+	)
+
+	for i, step := range steps {
+		stdout := newPrefixWriter(r.Stdout, &mu, step.Label)
+		stderr := newPrefixWriter(r.Stderr, &mu, step.Label)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer stdout.Flush()
+			defer stderr.Flush()
+
+			codes[i], errs[i] = r.runStep(ctx, step, nil, stdout, stderr, false)
+		}()
+	}
+	wg.Wait()
+
+	for _, code := range codes {
+		if code != 0 {
+			return code, errors.Join(errs...)
+		}
+	}
+
+	return 0, errors.Join(errs...)
+}
+
+func (r *Runner) runStep(
+	ctx context.Context, step Step,
+	stdin io.Reader, stdout, stderr io.Writer, tty bool,
+) (int, error) {
+	// NOTE: tty влияет только на команды на удаленном хосте
+	executor := utils.NewExecutor(step.Host, tty)
+
+	r.emit(Event{Kind: EventStart, Step: step, Time: time.Now()})
+
+	cmd, err := executor.Command(step.Command, step.Dir, step.Env)
+	if err != nil {
+		return r.exit(step, fmt.Errorf("failed to create command instance: %w", err))
+	}
+
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Start(); err != nil {
+		return r.exit(step, fmt.Errorf("failed to start exec command: %w", err))
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+			return
+		}
+
+		// NOTE: на windows прерывание не поддерживается, поэтому процесс сразу завершается
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			_ = cmd.Process.Kill()
+			return
+		}
+
+		select {
+		case <-time.After(r.killDelay()):
+			_ = cmd.Process.Kill()
+		case <-done:
+		}
+	}()
+
+	if err := cmd.Wait(); err != nil {
+		return r.exit(step, fmt.Errorf("failed to wait command: %w", err))
+	}
+
+	return r.exit(step, nil)
+}
+
+// exit отправляет событие завершения команды и возвращает ее код.
+func (r *Runner) exit(step Step, err error) (int, error) {
+	code := utils.ExitCode(err)
+	r.emit(Event{Kind: EventExit, Step: step, Time: time.Now(), ExitCode: code, Err: err})
+
+	return code, err
+}
+
+func (r *Runner) killDelay() time.Duration {
+	if r.KillDelay > 0 {
+		return r.KillDelay
+	}
+
+	return defaultKillDelay
+}
+
+func (r *Runner) emit(event Event) {
+	if r.OnEvent != nil {
+		r.OnEvent(event)
+	}
+}
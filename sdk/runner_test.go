@@ -0,0 +1,91 @@
+package sdk_test
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/algrvvv/ali/sdk"
+)
+
+func TestRunner(t *testing.T) {
+	var (
+		out    bytes.Buffer
+		mu     sync.Mutex
+		events []string
+	)
+
+	runner := &sdk.Runner{
+		Stdout: &out,
+		OnEvent: func(event sdk.Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, string(event.Kind)+" "+event.Step.Label)
+		},
+	}
+
+	plan := &sdk.Plan{Steps: []sdk.Step{
+		{Label: "one", Command: "echo one"},
+		{Label: "two", Command: "exit 3"},
+		{Label: "three", Command: "echo three"},
+	}}
+
+	code, err := runner.Run(context.Background(), plan)
+	if code != 3 || err == nil {
+		t.Errorf("ERROR: want: exit code 3 with error; got: %d, %v", code, err)
+	}
+
+	if strings.TrimSpace(out.String()) != "one" {
+		t.Errorf("ERROR: want: steps after failed one are skipped; got: %q", out.String())
+	}
+
+	if strings.Join(events, ", ") != "start one, exit one, start two, exit two" {
+		t.Errorf("ERROR: unexpected events: %v", events)
+	} else {
+		t.Logf("SUCCESS! got events: %v", events)
+	}
+
+	out.Reset()
+	plan = &sdk.Plan{Parallel: true, Steps: []sdk.Step{
+		{Label: "a", Command: "echo first; echo second"},
+		{Label: "b", Command: "printf partial"},
+	}}
+
+	code, err = runner.Run(context.Background(), plan)
+	if code != 0 || err != nil {
+		t.Fatalf("ERROR: want: success; got: %d, %v", code, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	sort.Strings(lines)
+	expected := []string{"[a] first", "[a] second", "[b] partial"}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("ERROR: want: %v; got: %v", expected, lines)
+	} else {
+		t.Logf("SUCCESS! got: %v", lines)
+	}
+}
+
+func TestRunnerCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	runner := &sdk.Runner{
+		KillDelay: 100 * time.Millisecond,
+		OnEvent: func(event sdk.Event) {
+			if event.Kind == sdk.EventStart {
+				cancel()
+			}
+		},
+	}
+
+	code, err := runner.Run(ctx, &sdk.Plan{Steps: []sdk.Step{{Label: "sleep", Command: "sleep 10"}}})
+	if code == 0 || err == nil {
+		t.Errorf("ERROR: want: canceled command; got: %d, %v", code, err)
+	} else {
+		t.Logf("SUCCESS! got: %d, %v", code, err)
+	}
+}
@@ -0,0 +1,45 @@
+package trust
+
+import (
+	"errors"
+
+	"github.com/spf13/viper"
+
+	"github.com/algrvvv/ali/parallel"
+	"github.com/algrvvv/ali/utils"
+)
+
+// Commands команды алиасов конфига file, включая команды из секции parallel.
+// Ключ - полное имя алиаса.
+func Commands(file string) (map[string][]string, error) {
+	v := viper.New()
+	utils.UseConfigFile(v, file)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	aliases, err := utils.LoadAliases(v)
+	errs := []error{err}
+
+	for name := range v.GetStringMap(parallel.ParallelPrefix) {
+		if _, ok := aliases[name]; !ok {
+			aliases[name] = utils.AliasEntry{AliasName: name, Parallel: true}
+		}
+	}
+
+	commands := make(map[string][]string, len(aliases))
+	for name, entry := range aliases {
+		list, err := parallel.GetCommands(v, &entry)
+		if err != nil {
+			errs = append(errs, err)
+			list = nil
+		}
+
+		commands[name] = make([]string, 0, len(list))
+		for _, command := range list {
+			commands[name] = append(commands[name], command.Command)
+		}
+	}
+
+	return commands, errors.Join(errs...)
+}
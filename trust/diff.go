@@ -0,0 +1,37 @@
+package trust
+
+import (
+	"maps"
+	"slices"
+)
+
+// Change изменение команд одного алиаса: у нового алиаса нет Old, у удаленного - New.
+type Change struct {
+	Alias string
+	Old   []string
+	New   []string
+}
+
+// Diff изменения команд алиасов между old и new по алфавиту имен.
+func Diff(old, new map[string][]string) []Change {
+	names := slices.Collect(maps.Keys(old))
+	for name := range new {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var changes []Change
+	for _, name := range names {
+		before, hadBefore := old[name]
+		after, hasAfter := new[name]
+		if hadBefore && hasAfter && slices.Equal(before, after) {
+			continue
+		}
+
+		changes = append(changes, Change{Alias: name, Old: before, New: after})
+	}
+
+	return changes
+}
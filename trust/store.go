@@ -0,0 +1,208 @@
+// Package trust хранит доверие к локальным и подключенным конфигам: ali загружает
+// такой конфиг, только если его содержимое совпадает с разрешенным через ali allow.
+package trust
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/algrvvv/ali/filelock"
+)
+
+// Status состояние доверия к конфигу.
+type Status string
+
+const (
+	// StatusAllowed конфиг разрешен и с тех пор не менялся
+	StatusAllowed Status = "allowed"
+	// StatusChanged конфиг изменился после ali allow
+	StatusChanged Status = "changed"
+	// StatusDenied конфиг запрещен через ali deny
+	StatusDenied Status = "denied"
+	// StatusUntrusted конфиг еще не разрешали
+	StatusUntrusted Status = "untrusted"
+	// StatusMissing разрешенного конфига больше нет
+	StatusMissing Status = "missing"
+)
+
+// Entry запись о конфиге. Commands - команды алиасов на момент ali allow,
+// с ними сравниваются новые команды при следующем ali allow.
+type Entry struct {
+	Path     string              `json:"-"`
+	Hash     string              `json:"hash"`
+	Denied   bool                `json:"denied,omitempty"`
+	Time     time.Time           `json:"time"`
+	Commands map[string][]string `json:"commands,omitempty"`
+}
+
+// Store база доверенных конфигов, ключ - абсолютный путь до конфига.
+type Store struct {
+	path  string
+	files map[string]Entry
+}
+
+// Path путь до базы: ~/.ali/trust.json.
+func Path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".ali", "trust.json"), nil
+}
+
+// New пустая база, которая будет сохранена в path.
+func New(path string) *Store {
+	return &Store{path: path, files: map[string]Entry{}}
+}
+
+// Load читает базу path; если ее еще нет, база пустая.
+func Load(path string) (*Store, error) {
+	files, err := readFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{path: path, files: files}, nil
+}
+
+func readFiles(path string) (map[string]Entry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]Entry{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	files := map[string]Entry{}
+	if err := json.Unmarshal(data, &files); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// Status состояние доверия к конфигу file.
+func (s *Store) Status(file string) Status {
+	path := absPath(file)
+	entry, ok := s.files[path]
+	if !ok {
+		return StatusUntrusted
+	}
+	if entry.Denied {
+		return StatusDenied
+	}
+
+	hash, err := hashFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return StatusMissing
+	}
+	if err != nil || hash != entry.Hash {
+		return StatusChanged
+	}
+
+	return StatusAllowed
+}
+
+// Trusted конфиг file можно загружать.
+func (s *Store) Trusted(file string) bool {
+	return s.Status(file) == StatusAllowed
+}
+
+// Entry запись о конфиге file.
+func (s *Store) Entry(file string) (Entry, bool) {
+	path := absPath(file)
+	entry, ok := s.files[path]
+	entry.Path = path
+
+	return entry, ok
+}
+
+// Entries все записи базы по алфавиту путей.
+func (s *Store) Entries() []Entry {
+	entries := make([]Entry, 0, len(s.files))
+	for path, entry := range s.files {
+		entry.Path = path
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b Entry) int { return strings.Compare(a.Path, b.Path) })
+
+	return entries
+}
+
+// Allow разрешает текущее содержимое конфига file.
+func (s *Store) Allow(file string) error {
+	path := absPath(file)
+	hash, err := hashFile(path)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: конфиг с ошибками тоже можно разрешить, тогда сравнивать будет не с чем
+	commands, _ := Commands(path)
+
+	return s.update(path, Entry{Hash: hash, Time: time.Now(), Commands: commands})
+}
+
+// Deny запрещает конфиг file, пока его снова не разрешат через Allow.
+func (s *Store) Deny(file string) error {
+	path := absPath(file)
+	hash, _ := hashFile(path)
+
+	// NOTE: команды последнего ali allow оставляем, чтобы потом показать изменения
+	commands := s.files[path].Commands
+
+	return s.update(path, Entry{Hash: hash, Denied: true, Time: time.Now(), Commands: commands})
+}
+
+// update перечитывает базу перед записью: ее могут менять другие запуски ali.
+func (s *Store) update(path string, entry Entry) error {
+	unlock, err := filelock.LockPath(s.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	files, err := readFiles(s.path)
+	if err != nil {
+		files = map[string]Entry{}
+	}
+	files[path] = entry
+
+	data, err := json.MarshalIndent(files, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := filelock.WriteFile(s.path, data, 0o600); err != nil {
+		return err
+	}
+
+	s.files = files
+	return nil
+}
+
+func absPath(file string) string {
+	if abs, err := filepath.Abs(file); err == nil {
+		return abs
+	}
+
+	return filepath.Clean(file)
+}
+
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
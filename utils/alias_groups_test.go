@@ -0,0 +1,124 @@
+package utils_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+func TestLoadAliasesGroups(t *testing.T) {
+	config := strings.Join([]string{
+		"aliases:",
+		"  build: go build",
+		"  db:",
+		"    desc: database",
+		"    dir: /srv",
+		"    env: {url: local, user: app}",
+		"    migrate: migrate up",
+		"    seed:",
+		"      dir: /tmp",
+		"      cmds: [seed]",
+		"    remote:",
+		"      host: db.example.com",
+		"      env: {url: remote}",
+		"      reset:",
+		"        env: {user: admin}",
+		"        cmds: [reset]",
+		"",
+	}, "\n")
+
+	v := viper.New()
+	v.SetConfigType(utils.YamlConfigurationType)
+	if err := v.ReadConfig(strings.NewReader(config)); err != nil {
+		t.Fatal(err)
+	}
+
+	aliases, err := utils.LoadAliases(v)
+	if err != nil {
+		t.Fatalf("ERROR: failed to load aliases: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		dir  string
+		host string
+		env  map[string]any
+	}{
+		{name: "build"},
+		{name: "db:migrate", dir: "/srv", env: map[string]any{"url": "local", "user": "app"}},
+		{name: "db:seed", dir: "/tmp", env: map[string]any{"url": "local", "user": "app"}},
+		{name: "db:remote:reset", dir: "/srv", host: "db.example.com", env: map[string]any{"url": "remote", "user": "admin"}},
+	}
+
+	if len(aliases) != len(tests) {
+		t.Fatalf("ERROR: want: %d aliases; got: %v", len(tests), aliases)
+	}
+
+	for _, test := range tests {
+		entry, ok := aliases[test.name]
+		if !ok {
+			t.Errorf("ERROR: alias %s not found", test.name)
+			continue
+		}
+
+		ok = entry.AliasName == test.name && entry.Dir == test.dir && entry.Host == test.host && len(entry.Env) == len(test.env)
+		for key, value := range test.env {
+			ok = ok && entry.Env[key] == value
+		}
+
+		if !ok {
+			t.Errorf("ERROR: %s: want: dir %q, host %q, env %v; got: %+v", test.name, test.dir, test.host, test.env, entry)
+		} else {
+			t.Logf("SUCCESS! %s: %+v", test.name, entry)
+		}
+	}
+
+	groups := utils.LoadGroups(v)
+	if len(groups) != 2 || groups["db"].Desc != "database" || groups["db:remote"].Host != "db.example.com" {
+		t.Errorf("ERROR: unexpected groups: %+v", groups)
+	}
+}
+
+func TestLoadAliasesReservedNames(t *testing.T) {
+	config := strings.Join([]string{
+		"aliases:",
+		"  docker:",
+		"    desc: docker tasks",
+		"    up: echo compose up",
+		"    ui: echo open ui",
+		"    output: echo output",
+		"    host: echo host alias",
+		"",
+	}, "\n")
+
+	v := viper.New()
+	v.SetConfigType(utils.YamlConfigurationType)
+	if err := v.ReadConfig(strings.NewReader(config)); err != nil {
+		t.Fatal(err)
+	}
+
+	aliases, err := utils.LoadAliases(v)
+	if err == nil || !strings.Contains(err.Error(), `"ui"`) || !strings.Contains(err.Error(), `"output"`) {
+		t.Errorf("ERROR: want: errors for ui and output; got: %v", err)
+	} else {
+		t.Logf("SUCCESS! got: %v", err)
+	}
+
+	if _, ok := aliases["docker:up"]; !ok || len(aliases) != 1 {
+		t.Errorf("ERROR: want: only docker:up; got: %v", aliases)
+	}
+
+	// host всегда настройка группы
+	if group := utils.LoadGroups(v)["docker"]; group.Host != "echo host alias" || group.Desc != "docker tasks" {
+		t.Errorf("ERROR: want: group settings desc and host; got: %+v", group)
+	}
+
+	for key, expected := range map[string]bool{"ui": true, "Schedule": true, "host": false, "desc": false, "up": false} {
+		if got := utils.IsReservedName(key); got != expected {
+			t.Errorf("ERROR: IsReservedName(%q): want: %v; got: %v", key, expected, got)
+		}
+	}
+}
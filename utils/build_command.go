@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/algrvvv/ali/logger"
+)
+
+// VarFlagPrefix префикс флага, который переопределяет переменную: -V_name=value.
+const VarFlagPrefix = "V_"
+
+var ErrEmptyCommand = errors.New("empty command")
+
+// BuildCommand подставляет в команду флаги, аргументы и переменные.
+// Флаги <flag> заменяются значением, остальные флаги дописываются в конец.
+// Флаги -V_<name>=<value> переопределяют переменную только для этой команды.
+// Аргументы и vars не изменяются.
+func BuildCommand(
+	command string, args []string,
+	flags map[string]string, vars map[string]string,
+) (string, error) {
+	// проверяем аргументы, чтобы при пробелах в них мы не получили их как разные аргументы
+	args = slices.Clone(args)
+	for i := range args {
+		if strings.Contains(args[i], " ") {
+			logger.SaveDebugf(
+				"founded arg that contains spaces: %s; quotation marks enabled",
+				args[i],
+			)
+			args[i] = fmt.Sprintf("\"%s\"", args[i])
+		}
+	}
+
+	vars = maps.Clone(vars)
+	if vars == nil {
+		vars = make(map[string]string)
+	}
+
+	// NOTE: сортируем флаги, чтобы дописанные в конец шли всегда в одном порядке
+	keys := slices.Sorted(maps.Keys(flags))
+	for _, key := range keys {
+		value := flags[key]
+		logger.SaveDebugf("got key: %s", key)
+
+		if strings.Contains(key, VarFlagPrefix) {
+			varToChange := strings.Replace(key, VarFlagPrefix, "", 1)
+			varToChange = strings.ToLower(strings.TrimLeft(varToChange, "-"))
+			logger.SaveDebugf("key: %s - contains V; var to change: %s", key, varToChange)
+
+			vars[varToChange] = value
+			continue
+		}
+
+		k := fmt.Sprintf("<%s>", strings.ReplaceAll(key, "-", ""))
+		logger.SaveDebugf("parse command for find flag: %s with value: %s", k, value)
+		if strings.Contains(command, k) {
+			command = strings.ReplaceAll(command, k, value)
+		} else {
+			if value == "" {
+				command += " " + key
+			} else {
+				command += " " + fmt.Sprintf("%s=%s", key, value)
+			}
+		}
+	}
+
+	cmdArgs := fmt.Sprintf("%s %s", command, strings.Join(args, " "))
+	logger.SaveDebugf("got cmd args: %s", cmdArgs)
+	if strings.TrimSpace(cmdArgs) == "" {
+		logger.SaveDebugf("got empty args")
+		return "", ErrEmptyCommand
+	}
+
+	logger.SaveDebugf("got vars: %v", vars)
+	return GetVariables(cmdArgs, vars), nil
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ReadConfigMap читает конфиг path в map с учетом его типа.
+// В отличие от viper ключи сохраняют регистр, а числа json остаются целыми, где это возможно.
+func ReadConfigMap(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeConfig(data, ConfigType(path))
+}
+
+// DecodeConfig разбирает конфиг типа typ.
+func DecodeConfig(data []byte, typ string) (map[string]any, error) {
+	config := make(map[string]any)
+
+	switch typ {
+	case YamlConfigurationType:
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, err
+		}
+	case TomlConfigurationType:
+		if err := toml.Unmarshal(data, &config); err != nil {
+			return nil, err
+		}
+	case JsonConfigurationType:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&config); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedConfigType, typ)
+	}
+
+	normalized, _ := normalizeConfigValue(config).(map[string]any)
+	if normalized == nil {
+		normalized = make(map[string]any)
+	}
+
+	return normalized, nil
+}
+
+// EncodeConfig записывает конфиг в формате typ. Комментарии исходного конфига не сохраняются.
+func EncodeConfig(config map[string]any, typ string) ([]byte, error) {
+	switch typ {
+	case YamlConfigurationType:
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(config); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case TomlConfigurationType:
+		// NOTE: в toml нет null, пустые значения просто пропускаем
+		return toml.Marshal(dropNulls(config))
+	case JsonConfigurationType:
+		data, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedConfigType, typ)
+	}
+}
+
+// normalizeConfigValue приводит значения разных форматов к общим типам:
+// map[string]any, []any, int64 и float64.
+func normalizeConfigValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = normalizeConfigValue(item)
+		}
+		return out
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[fmt.Sprint(key)] = normalizeConfigValue(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalizeConfigValue(item)
+		}
+		return out
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case int:
+		return int64(v)
+	default:
+		return v
+	}
+}
+
+func dropNulls(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			if item != nil {
+				out[key] = dropNulls(item)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, 0, len(v))
+		for _, item := range v {
+			if item != nil {
+				out = append(out, dropNulls(item))
+			}
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,57 @@
+package utils_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+func TestConvertConfig(t *testing.T) {
+	source := []byte(`app:
+  strict: true
+vars:
+  name: ali
+env:
+  PORT: 8080
+aliases:
+  build:
+    desc: build {{name}}
+    cmds:
+      - go build ./...
+      - go vet ./...
+    timeout: 1.5
+  db:
+    desc: database
+    migrate: make migrate
+parallel:
+  dev:
+    - command: go run .
+      label: api
+`)
+
+	want, err := utils.DecodeConfig(source, utils.YamlConfigurationType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, typ := range []string{utils.TomlConfigurationType, utils.JsonConfigurationType, utils.YamlConfigurationType} {
+		data, err := utils.EncodeConfig(want, typ)
+		if err != nil {
+			t.Errorf("ERROR: %s: failed to encode: %v", typ, err)
+			continue
+		}
+
+		got, err := utils.DecodeConfig(data, typ)
+		if err != nil {
+			t.Errorf("ERROR: %s: failed to decode: %v\n%s", typ, err, data)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ERROR: %s: want: %v; got: %v", typ, want, got)
+		} else {
+			t.Logf("SUCCESS! %s round trip", typ)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/algrvvv/ali/logger"
+)
+
+// Executor создает процесс для уже подготовленной команды алиаса:
+// с подставленными аргументами, флагами и переменными.
+type Executor interface {
+	Command(command string, dir string, envs map[string]any) (*exec.Cmd, error)
+}
+
+// NewExecutor возвращает исполнителя для хоста из `host` алиаса.
+// Пустой хост или local - команда выполняется на этой машине.
+// tty - нужен ли удаленной команде терминал.
+func NewExecutor(host string, tty bool) Executor {
+	if host == "" || host == "local" {
+		return LocalExecutor{}
+	}
+
+	return SSHExecutor{Host: host, TTY: tty}
+}
+
+// LocalExecutor выполняет команду в локальной оболочке.
+type LocalExecutor struct{}
+
+func (LocalExecutor) Command(command string, dir string, envs map[string]any) (*exec.Cmd, error) {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("cmd.exe", "/C", command)
+	case "linux", "darwin":
+		cmd = exec.Command("sh", "-c", command)
+	default:
+		logger.SaveDebugf("Unsupported OS")
+		return nil, errors.New("unsupported OS")
+	}
+
+	if dir != "" && dir != "." {
+		logger.SaveDebugf("entry use dir for exec: %q", dir)
+		if strings.HasPrefix(dir, "~") {
+			logger.SaveDebugf("user use ~ in dir param")
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, errors.New("failed to get user home dir")
+			}
+
+			logger.SaveDebugf("got home user dir: %q", home)
+			dir = strings.Replace(dir, "~", home, 1)
+			logger.SaveDebugf("command dir after change ~ to home dir: %q", dir)
+		}
+	}
+	cmd.Dir = dir
+
+	// работаем с env
+	cmdEnv := os.Environ()
+	for name, value := range envs {
+		n := strings.ToUpper(name)
+
+		cmdEnv = append(cmdEnv, fmt.Sprintf("%s=%v", n, value))
+		logger.SaveDebugf("set new env variable: %s=%v", n, value)
+	}
+	cmd.Env = cmdEnv
+
+	return cmd, nil
+}
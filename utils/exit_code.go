@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// ExitCode возвращает код завершения команды по ошибке от cmd.Wait/cmd.Run.
+// Если команда не запустилась или код получить нельзя - возвращается -1.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+
+	return -1
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"path/filepath"
+
+	"github.com/spf13/viper"
+
+	"github.com/algrvvv/ali/logger"
+)
+
+// RootConfigKey ключ локального конфига, на котором поиск родительских конфигов останавливается.
+const RootConfigKey = "root"
+
+// FindLocalConfigs ищет локальные конфиги с именем name (или name.toml, name.json и т.д.)
+// в dir и во всех родительских директориях. Поиск останавливается в корне файловой системы,
+// в корне git репозитория или на конфиге с `root: true`. Ближайший конфиг - первый.
+// trusted проверяет конфиг до чтения root: недоверенный конфиг не может остановить поиск.
+// Nil - доверять всем.
+func FindLocalConfigs(dir string, name string, trusted func(path string) bool) ([]string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []string
+	for {
+		if path, ok := FindConfigIn(dir, name); ok {
+			configs = append(configs, path)
+
+			// NOTE: содержимое недоверенного конфига не читаем вовсе
+			if (trusted == nil || trusted(path)) && isRootConfig(path) {
+				logger.SaveDebugf("config %s is root; stop config discovery", path)
+				break
+			}
+		}
+
+		if FileExists(filepath.Join(dir, ".git")) {
+			logger.SaveDebugf("found repository root %s; stop config discovery", dir)
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return configs, nil
+}
+
+func isRootConfig(path string) bool {
+	v := viper.New()
+	UseConfigFile(v, path)
+	if err := v.ReadInConfig(); err != nil {
+		logger.SaveDebugf("failed to read config %s: %v", path, err)
+		return false
+	}
+
+	return v.GetBool(RootConfigKey)
+}
@@ -0,0 +1,98 @@
+package utils_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+func TestFindLocalConfigs(t *testing.T) {
+	root := t.TempDir()
+	api := filepath.Join(root, "project", "src", "api")
+	if err := os.MkdirAll(api, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		filepath.Join(root, ".ali"):                   "aliases:\n  a: echo a\n",
+		filepath.Join(root, "project", ".ali"):        "aliases:\n  b: echo b\n",
+		filepath.Join(root, "project", "src", ".ali"): "aliases:\n  c: echo c\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	check := func(name string, expected []string) {
+		got, err := utils.FindLocalConfigs(api, ".ali", nil)
+		if err != nil || !slices.Equal(got, expected) {
+			t.Errorf("ERROR: %s: want: %v; got: %v (%v)", name, expected, got, err)
+		} else {
+			t.Logf("SUCCESS! %s: got: %v", name, got)
+		}
+	}
+
+	check("all parents", []string{
+		filepath.Join(root, "project", "src", ".ali"),
+		filepath.Join(root, "project", ".ali"),
+		filepath.Join(root, ".ali"),
+	})
+
+	if err := os.Mkdir(filepath.Join(root, "project", ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	check("repository root", []string{
+		filepath.Join(root, "project", "src", ".ali"),
+		filepath.Join(root, "project", ".ali"),
+	})
+
+	err := os.WriteFile(filepath.Join(root, "project", "src", ".ali"), []byte("root: true\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("root config", []string{filepath.Join(root, "project", "src", ".ali")})
+
+	// root недоверенного конфига не учитывается
+	untrusted := filepath.Join(root, "project", "src", ".ali")
+	got, err := utils.FindLocalConfigs(api, ".ali", func(path string) bool { return path != untrusted })
+	expected := []string{untrusted, filepath.Join(root, "project", ".ali")}
+	if err != nil || !slices.Equal(got, expected) {
+		t.Errorf("ERROR: untrusted root config: want: %v; got: %v (%v)", expected, got, err)
+	} else {
+		t.Logf("SUCCESS! untrusted root config: got: %v", got)
+	}
+}
+
+func TestFindLocalConfigsFormats(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "project")
+	if err := os.MkdirAll(filepath.Join(project, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		filepath.Join(root, ".ali.json"):    `{"aliases": {"a": "echo a"}}`,
+		filepath.Join(project, ".ali.toml"): "[aliases]\nb = 'echo b'\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := utils.FindLocalConfigs(project, ".ali", nil)
+	expected := []string{filepath.Join(project, ".ali.toml")}
+	if err != nil || !slices.Equal(got, expected) {
+		t.Errorf("ERROR: want: %v; got: %v (%v)", expected, got, err)
+	} else {
+		t.Logf("SUCCESS! got: %v", got)
+	}
+
+	if typ := utils.ConfigType(got[0]); typ != utils.TomlConfigurationType {
+		t.Errorf("ERROR: want type: %s; got: %s", utils.TomlConfigurationType, typ)
+	}
+}
@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// StrictKey включает строгий режим: команда с неразрешенными ссылками не запускается.
+const StrictKey = "strict"
+
+var (
+	varRefRegexp = regexp.MustCompile(`\{\{(\w+)\}\}`)
+	// <param> подставляется из флага --param, дефисы в имени флага отбрасываются,
+	// поэтому <your-token> или <YOUR TOKEN> - просто текст
+	paramRefRegexp = regexp.MustCompile(`<([A-Za-z_]\w*)>`)
+	// $NAME и ${NAME}, но не ${NAME:-default} и подобные со значением по умолчанию
+	envRefRegexp = regexp.MustCompile(`\$(?:\{([A-Za-z_][A-Za-z0-9_]*)\}|([A-Za-z_][A-Za-z0-9_]*))`)
+	// переменные, которые задает сама команда: NAME=..., for NAME in, read NAME
+	shellAssignRegexp = regexp.MustCompile(`(?:^|[\s;&|(])(?:([A-Za-z_][A-Za-z0-9_]*)=|for\s+([A-Za-z_][A-Za-z0-9_]*)\s+in\b|read\s+(?:-\w+\s+)*([A-Za-z_][A-Za-z0-9_]*))`)
+)
+
+// переменные, которые задает сама оболочка, а не окружение
+var shellVars = map[string]bool{
+	"IFS": true, "PWD": true, "OLDPWD": true, "PPID": true, "RANDOM": true, "SECONDS": true,
+	"LINENO": true, "UID": true, "EUID": true, "HOSTNAME": true, "OPTARG": true, "OPTIND": true, "REPLY": true,
+}
+
+// FindUnresolved ищет в готовой команде ссылки, которые не удалось подставить:
+// переменные {{var}}, параметры <param> и переменные окружения $ENV.
+// envs - переменные окружения алиаса в дополнение к окружению ali;
+// при checkEnv = false $ENV не проверяются (например, для команды на удаленном хосте).
+func FindUnresolved(command string, envs map[string]any, checkEnv bool) []string {
+	var unresolved []string
+	add := func(ref string) {
+		if !slices.Contains(unresolved, ref) {
+			unresolved = append(unresolved, ref)
+		}
+	}
+
+	for _, match := range varRefRegexp.FindAllString(command, -1) {
+		add(match)
+	}
+
+	for _, match := range paramRefRegexp.FindAllStringSubmatchIndex(command, -1) {
+		if isParamRef(command, match[0], match[1], command[match[2]:match[3]]) {
+			add(command[match[0]:match[1]])
+		}
+	}
+
+	if !checkEnv {
+		return unresolved
+	}
+
+	assigned := make(map[string]bool)
+	for _, match := range shellAssignRegexp.FindAllStringSubmatch(command, -1) {
+		for _, name := range match[1:] {
+			if name != "" {
+				assigned[name] = true
+			}
+		}
+	}
+
+	// NOTE: в одинарных кавычках оболочка $ не раскрывает
+	for _, match := range envRefRegexp.FindAllStringSubmatch(withoutSingleQuoted(command), -1) {
+		name := match[1] + match[2]
+		if assigned[name] || shellVars[name] || hasEnv(name, envs) {
+			continue
+		}
+		add("$" + name)
+	}
+
+	return unresolved
+}
+
+// UnresolvedError команда содержит ссылки, которые не удалось подставить.
+type UnresolvedError struct {
+	Command    string
+	Unresolved []string
+}
+
+func (e *UnresolvedError) Error() string {
+	return fmt.Sprintf("unresolved references in %q: %s", e.Command, strings.Join(e.Unresolved, ", "))
+}
+
+// isParamRef отличает <param> от перенаправлений оболочки и html:
+// `sort <in>out` (сразу за > идет имя файла), `cat <<EOF>` и `<b>bold</b>`.
+func isParamRef(command string, start, end int, name string) bool {
+	if start > 0 && command[start-1] == '<' {
+		return false
+	}
+
+	if end < len(command) && isWordByte(command[end]) {
+		return false
+	}
+
+	return !strings.Contains(command, "</"+name+">")
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+func hasEnv(name string, envs map[string]any) bool {
+	if _, ok := os.LookupEnv(name); ok {
+		return true
+	}
+
+	for key := range envs {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withoutSingleQuoted убирает из команды строки в одинарных кавычках и экранированные символы.
+// Внутри двойных кавычек одинарная кавычка - обычный символ.
+func withoutSingleQuoted(command string) string {
+	var b strings.Builder
+	var single, double, escaped bool
+	for _, r := range command {
+		switch {
+		case escaped:
+			// NOTE: экранированный \$ оболочка тоже не раскрывает
+			escaped = false
+			continue
+		case single:
+			if r == '\'' {
+				single = false
+			}
+			continue
+		case r == '\\':
+			escaped = true
+			continue
+		case r == '"':
+			double = !double
+		case r == '\'' && !double:
+			single = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
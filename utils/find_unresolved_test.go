@@ -0,0 +1,41 @@
+package utils_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+func TestFindUnresolved(t *testing.T) {
+	t.Setenv("ALI_TEST_DEFINED", "1")
+
+	tests := []struct {
+		command  string
+		envs     map[string]any
+		expected []string
+	}{
+		{command: `echo "{{name}} <user>"`, expected: []string{"{{name}}", "<user>"}},
+		{command: `echo $ALI_TEST_DEFINED $ALI_TEST_MISSING ${ALI_TEST_OTHER}`, expected: []string{"$ALI_TEST_MISSING", "$ALI_TEST_OTHER"}},
+		{command: `echo $port`, envs: map[string]any{"PORT": 80}},
+		{command: `echo '$LITERAL' \$ESCAPED "it's $ALI_TEST_DEFINED" ${WITH_DEFAULT:-x} $1`},
+		{command: `for F in *; do echo $F; done; X=1; echo $X`},
+		{command: `echo "it's $ALI_TEST_MISSING"`, expected: []string{"$ALI_TEST_MISSING"}},
+		{command: `tar czf <name>.tgz <dir_name>/ && echo <Tag>`, expected: []string{"<name>", "<dir_name>", "<Tag>"}},
+		// не параметры: перенаправления, html и текст-заглушки
+		{command: `sort <input>sorted.txt`},
+		{command: `cat <<EOF> out.txt`},
+		{command: `echo '<b>bold</b> <p>text</p>' > page.html`},
+		{command: `echo "set <your-token> or <YOUR TOKEN>" && test 1 <2`},
+		{command: `echo "<br/> <1> <-v>"`},
+	}
+
+	for _, test := range tests {
+		got := utils.FindUnresolved(test.command, test.envs, true)
+		if !slices.Equal(got, test.expected) {
+			t.Errorf("ERROR: %s: want: %v; got: %v", test.command, test.expected, got)
+		} else {
+			t.Logf("SUCCESS! %s: got: %v", test.command, got)
+		}
+	}
+}
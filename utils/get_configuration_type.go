@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
-
-	"github.com/algrvvv/ali/logger"
+	"path/filepath"
+	"strings"
 
 	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
+
+	"github.com/algrvvv/ali/logger"
 )
 
 const (
@@ -19,8 +22,54 @@ const (
 
 var ErrUnsupportedConfigType = errors.New("unsupported config type")
 
-// GetConfigurationType функция для определения типа конфигурации.
-// Deprecated: функция больше не используется
+// ConfigExtensions расширения конфигов по типам; конфиг без расширения (.ali) определяется по содержимому.
+var ConfigExtensions = map[string]string{
+	".yml":  YamlConfigurationType,
+	".yaml": YamlConfigurationType,
+	".toml": TomlConfigurationType,
+	".json": JsonConfigurationType,
+}
+
+// ConfigFileNames имена, под которыми ищется конфиг name: .ali, .ali.yml, .ali.toml и т.д.
+func ConfigFileNames(name string) []string {
+	return []string{name, name + ".yml", name + ".yaml", name + ".toml", name + ".json"}
+}
+
+// FindConfigIn первый конфиг с именем name из ConfigFileNames в директории dir.
+func FindConfigIn(dir, name string) (string, bool) {
+	for _, file := range ConfigFileNames(name) {
+		path := filepath.Join(dir, file)
+		// NOTE: в домашней директории .ali - это директория глобального конфига, а не файл
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path, true
+		}
+	}
+
+	return "", false
+}
+
+// ConfigType тип конфига: по расширению, а без него - по содержимому.
+// Если тип определить не удалось, конфиг считается yaml, чтобы ошибку показал его разбор.
+func ConfigType(path string) string {
+	if typ, ok := ConfigExtensions[strings.ToLower(filepath.Ext(path))]; ok {
+		return typ
+	}
+
+	typ, err := GetConfigurationType(path)
+	if err != nil {
+		return YamlConfigurationType
+	}
+
+	return typ
+}
+
+// UseConfigFile настраивает v на чтение конфига path с его типом.
+func UseConfigFile(v *viper.Viper, path string) {
+	v.SetConfigFile(path)
+	v.SetConfigType(ConfigType(path))
+}
+
+// GetConfigurationType определяет тип конфига по содержимому.
 func GetConfigurationType(path string) (string, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -3,25 +3,8 @@ package utils
 import (
 	"regexp"
 	"strings"
-
-	"github.com/spf13/viper"
 )
 
-type varConfig struct {
-	Vars map[string]string `mapstructure:"vars"`
-}
-
-func GetVars() (map[string]string, error) {
-	var vars varConfig
-
-	err := viper.Unmarshal(&vars)
-	if err != nil {
-		return nil, err
-	}
-
-	return vars.Vars, nil
-}
-
 func GetVariables(input string, vars map[string]string) string {
 	re := regexp.MustCompile(`\{\{(\w+)\}\}`)
 	return re.ReplaceAllStringFunc(input, func(match string) string {
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"maps"
+	"runtime"
+	"slices"
+
+	"github.com/algrvvv/ali/logger"
+)
+
+// Invocation контекст одного запуска алиаса: переменные, окружение, аргументы, флаги
+// и директория. Данные копируются при создании и дальше только читаются,
+// поэтому один контекст можно использовать из горутин параллельных команд.
+type Invocation struct {
+	vars   map[string]string
+	env    map[string]any
+	params []string
+	flags  map[string]string
+	dir    string
+	strict bool
+}
+
+// InvocationOptions данные для создания контекста запуска.
+type InvocationOptions struct {
+	Vars   map[string]string
+	Env    map[string]any
+	Params []string
+	Flags  map[string]string
+	// Dir директория команд по умолчанию
+	Dir string
+	// Strict не собирать команды с неразрешенными ссылками
+	Strict bool
+}
+
+func NewInvocation(opts InvocationOptions) *Invocation {
+	return &Invocation{
+		vars:   maps.Clone(opts.Vars),
+		env:    maps.Clone(opts.Env),
+		params: slices.Clone(opts.Params),
+		flags:  maps.Clone(opts.Flags),
+		dir:    opts.Dir,
+		strict: opts.Strict,
+	}
+}
+
+// Vars копия переменных без переопределений -V_.
+func (i *Invocation) Vars() map[string]string { return maps.Clone(i.vars) }
+
+// Env копия переменных окружения.
+func (i *Invocation) Env() map[string]any { return maps.Clone(i.env) }
+
+// Params копия аргументов.
+func (i *Invocation) Params() []string { return slices.Clone(i.params) }
+
+// Flags копия флагов.
+func (i *Invocation) Flags() map[string]string { return maps.Clone(i.flags) }
+
+func (i *Invocation) Dir() string { return i.dir }
+
+func (i *Invocation) Strict() bool { return i.strict }
+
+// Build подставляет в команду аргументы, флаги и переменные запуска.
+// local - команда выполняется локально и $ENV раскрываются из окружения ali.
+func (i *Invocation) Build(command string, local bool) (string, error) {
+	result, err := BuildCommand(command, i.params, i.flags, i.vars)
+	if err != nil {
+		return "", err
+	}
+
+	if i.strict {
+		// NOTE: $ENV на удаленном хосте и в cmd.exe раскрываются не из нашего окружения
+		unresolved := FindUnresolved(result, i.env, local && runtime.GOOS != "windows")
+		if len(unresolved) > 0 {
+			logger.SaveDebugf("unresolved references: %v", unresolved)
+			return "", &UnresolvedError{Command: result, Unresolved: unresolved}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package utils_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+func TestInvocationBuild(t *testing.T) {
+	vars := map[string]string{"stage": "dev"}
+	flags := map[string]string{"-V_stage": "prod", "--host": "web"}
+	params := []string{"two words"}
+
+	inv := utils.NewInvocation(utils.InvocationOptions{Vars: vars, Params: params, Flags: flags})
+
+	// NOTE: изменения после создания не должны попадать в контекст
+	vars["stage"] = "changed"
+	flags["--extra"] = ""
+	params[0] = "changed"
+
+	var wg sync.WaitGroup
+	results := make([]string, 16)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			cmd, err := inv.Build(fmt.Sprintf("deploy-%d {{stage}} <host>", i), true)
+			if err != nil {
+				t.Errorf("ERROR: failed to build command: %v", err)
+			}
+			results[i] = cmd
+		}()
+	}
+	wg.Wait()
+
+	for i, got := range results {
+		expected := fmt.Sprintf(`deploy-%d prod web "two words"`, i)
+		if got != expected {
+			t.Errorf("ERROR: want: %s; got: %s", expected, got)
+		}
+	}
+	t.Logf("SUCCESS! got: %s", results[0])
+
+	if inv.Vars()["stage"] != "dev" {
+		t.Errorf("ERROR: want: vars without -V_ overrides; got: %v", inv.Vars())
+	}
+
+	strictInv := utils.NewInvocation(utils.InvocationOptions{Strict: true})
+	if _, err := strictInv.Build("deploy {{stage}}", true); err == nil {
+		t.Errorf("ERROR: want: unresolved references error in strict mode")
+	}
+}
@@ -1,56 +1,147 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
+
+	"github.com/algrvvv/ali/notify"
+	"github.com/algrvvv/ali/schedule"
 )
 
 type AliasEntry struct {
-	AliasName string         `mapstructure:"alias"`
-	Aliases   []string       `mapstructure:"aliases"`
-	Cmds      []string       `mapstructure:"cmds"`
-	Desc      string         `mapstructure:"desc"`
-	Env       map[string]any `mapstructure:"env"`
-	Parallel  bool           `mapstructure:"parallel"`
-	Dir       string         `mapstructure:"dir"`
+	AliasName  string         `mapstructure:"alias"`
+	Aliases    []string       `mapstructure:"aliases"`
+	Cmds       []string       `mapstructure:"cmds"`
+	Desc       string         `mapstructure:"desc"`
+	Env        map[string]any `mapstructure:"env"`
+	Parallel   bool           `mapstructure:"parallel"`
+	Dir        string         `mapstructure:"dir"`
+	UI         string         `mapstructure:"ui"`
+	TmuxLayout string         `mapstructure:"tmux_layout"`
+	OutputMode string         `mapstructure:"output"`
+	Timestamps string         `mapstructure:"timestamps"`
+	LogDir     string         `mapstructure:"log_dir"`
+	TTY        bool           `mapstructure:"tty"`
+	// Host хост для запуска команд по ssh: user@server или хост из ~/.ssh/config
+	Host   string         `mapstructure:"host"`
+	Notify *notify.Config `mapstructure:"notify"`
+	// Schedule расписание для ali daemon
+	Schedule *schedule.Config `mapstructure:"schedule"`
+}
+
+// LoadAliases разбирает алиасы конфигурации. Алиасы групп получают полные имена: db:migrate.
+// Алиасы, которые не удалось разобрать, пропускаются, а их ошибки возвращаются вместе
+// (подробнее их показывает ali validate).
+func LoadAliases(v *viper.Viper) (map[string]AliasEntry, error) {
+	l := newAliasLoader()
+	l.load(v.GetStringMap("aliases"), "", AliasGroup{})
+
+	return l.aliases, errors.Join(l.errs...)
+}
+
+// LoadGroups разбирает группы алиасов конфигурации, ключ - полное имя группы.
+func LoadGroups(v *viper.Viper) map[string]AliasGroup {
+	l := newAliasLoader()
+	l.load(v.GetStringMap("aliases"), "", AliasGroup{})
+
+	return l.groups
 }
 
-func LoadAliases(v *viper.Viper) map[string]AliasEntry {
-	raw := v.GetStringMap("aliases")
-	out := make(map[string]AliasEntry)
+type aliasLoader struct {
+	aliases map[string]AliasEntry
+	groups  map[string]AliasGroup
+	errs    []error
+}
+
+func newAliasLoader() *aliasLoader {
+	return &aliasLoader{
+		aliases: make(map[string]AliasEntry),
+		groups:  make(map[string]AliasGroup),
+	}
+}
 
+func (l *aliasLoader) load(raw map[string]any, prefix string, group AliasGroup) {
 	for key, val := range raw {
+		name := prefix + key
+
 		switch v := val.(type) {
 		case string:
-			out[key] = AliasEntry{
-				AliasName: key,
+			entry := AliasEntry{
+				AliasName: name,
 				Cmds:      []string{v},
 			}
+			group.apply(&entry)
+			l.aliases[name] = entry
 		case map[string]any:
-			var entry AliasEntry
-			decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
-				Result:  &entry,
-				TagName: "mapstructure",
-			})
-			if err != nil {
-				// WARN: не забыть добавить обработку ошибки
-				panic(err)
+			if IsAliasGroup(slices.Collect(maps.Keys(v))) {
+				l.loadGroup(name, v, group)
+				continue
 			}
 
-			err = decoder.Decode(v)
-			if err != nil {
-				// WARN: не забыть добавить обработку ошибки
-				panic(err)
+			var entry AliasEntry
+			if err := decodeAlias(v, &entry); err != nil {
+				l.errs = append(l.errs, fmt.Errorf("alias %q: %w", name, err))
+				continue
 			}
 
-			entry.AliasName = key
-			out[key] = entry
+			entry.AliasName = name
+			group.apply(&entry)
+			l.aliases[name] = entry
 		default:
-			fmt.Printf("unsupported alias value type for %q: %T\n", key, v)
+			l.errs = append(l.errs, fmt.Errorf("alias %q: unsupported value type: %T", name, v))
 		}
 	}
+}
+
+func (l *aliasLoader) loadGroup(name string, raw map[string]any, parent AliasGroup) {
+	settings := make(map[string]any)
+	children := make(map[string]any)
+	for key, val := range raw {
+		switch {
+		case IsGroupField(key):
+			settings[key] = val
+		case IsReservedName(key):
+			l.errs = append(l.errs, fmt.Errorf("group %q: %q is not supported in group (use: %s) and can't be an alias name",
+				name, key, strings.Join(GroupFields, ", ")))
+		default:
+			children[key] = val
+		}
+	}
+
+	var group AliasGroup
+	if err := decodeAlias(settings, &group); err != nil {
+		l.errs = append(l.errs, fmt.Errorf("group %q: %w", name, err))
+		return
+	}
+
+	group.Name = name
+	group = group.inherit(parent)
+	l.groups[name] = group
+
+	l.load(children, name+GroupSeparator, group)
+}
+
+// decodeAlias разбирает описание алиаса или настройки группы.
+func decodeAlias(raw map[string]any, result any) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:  result,
+		TagName: "mapstructure",
+		// NOTE: чтобы можно было писать, например, log_dir: true
+		WeaklyTypedInput: true,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			schedule.DecodeHook(),
+		),
+	})
+	if err != nil {
+		return err
+	}
 
-	return out
+	return decoder.Decode(raw)
 }
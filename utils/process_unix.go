@@ -0,0 +1,43 @@
+//go:build !windows
+
+package utils
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+)
+
+// SetProcessGroup запускает команду в отдельной группе процессов,
+// чтобы потом можно было завершить все дерево процессов целиком.
+func SetProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// TerminateProcessGroup мягко завершает группу процессов (SIGTERM).
+func TerminateProcessGroup(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGTERM)
+}
+
+// KillProcessGroup принудительно завершает группу процессов (SIGKILL).
+func KillProcessGroup(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGKILL)
+}
+
+// SetNewSession запускает команду в новой сессии, отвязанной от терминала,
+// чтобы она продолжала работать после закрытия терминала.
+func SetNewSession(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+}
+
+// ProcessAlive проверяет, что процесс с таким pid существует.
+func ProcessAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
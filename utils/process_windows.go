@@ -0,0 +1,60 @@
+//go:build windows
+
+package utils
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// SetProcessGroup запускает команду в отдельной группе процессов,
+// чтобы потом можно было завершить все дерево процессов целиком.
+func SetProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+}
+
+// TerminateProcessGroup завершает дерево процессов.
+func TerminateProcessGroup(pid int) error {
+	return exec.Command("taskkill", "/T", "/PID", strconv.Itoa(pid)).Run()
+}
+
+// KillProcessGroup принудительно завершает дерево процессов.
+func KillProcessGroup(pid int) error {
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(pid)).Run()
+}
+
+// флаги windows, которых нет в пакете syscall
+const (
+	detachedProcess                = 0x00000008
+	processQueryLimitedInformation = 0x1000
+	stillActive                    = 259
+)
+
+// SetNewSession запускает команду без консоли в отдельной группе процессов,
+// чтобы она продолжала работать после закрытия терминала.
+func SetNewSession(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP | detachedProcess
+}
+
+// ProcessAlive проверяет, что процесс с таким pid еще работает.
+func ProcessAlive(pid int) bool {
+	handle, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer syscall.CloseHandle(handle)
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(handle, &code); err != nil {
+		return false
+	}
+
+	return code == stillActive
+}
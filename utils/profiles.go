@@ -0,0 +1,15 @@
+package utils
+
+// ключи и переменная окружения для выбора профиля
+const (
+	ProfilesKey       = "profiles"
+	DefaultProfileKey = "app.profile"
+	ProfileEnv        = "ALI_PROFILE"
+)
+
+// Profile набор vars и env, который накладывается поверх vars и env конфигов:
+// например, разные адреса для dev и prod.
+type Profile struct {
+	Vars map[string]string `mapstructure:"vars"`
+	Env  map[string]any    `mapstructure:"env"`
+}
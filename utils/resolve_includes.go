@@ -0,0 +1,223 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/algrvvv/ali/logger"
+	"github.com/algrvvv/ali/remote"
+)
+
+// IncludeKey ключ конфига со списком подключаемых конфигов.
+const IncludeKey = "include"
+
+// ErrIncludeCycle конфиги подключают друг друга по кругу.
+var ErrIncludeCycle = errors.New("include cycle")
+
+// ResolveIncludes собирает все конфиги, подключенные из files и из подключенных
+// конфигов рекурсивно. Конфиг идет сразу после подключившего его, каждый - один раз.
+// Ошибки отдельных подключений не мешают загрузить остальные.
+// trusted проверяет подключенный конфиг до чтения его include: недоверенный конфиг
+// попадает в результат, но то, что он подключает, не читается и не скачивается. Nil - доверять всем.
+//
+// Путь в include может быть:
+//   - файлом или директорией (тогда берется <dir>/.ali);
+//   - относительным - от директории подключающего конфига;
+//   - шаблоном: aliases.d/*.yml;
+//   - необязательным: ?path - отсутствие файла не ошибка;
+//   - удаленным: git+https://host/repo.git//path@ref или https://host/file.yml#sha256=<sum>.
+func ResolveIncludes(files []string, trusted func(path string) bool) ([]string, []error) {
+	r := newIncludeResolver(false, trusted)
+	r.resolve(files)
+
+	return r.out, r.errs
+}
+
+// UpdateIncludes заново скачивает все удаленные конфиги, подключенные из files,
+// и возвращает обновленные источники. Include недоверенных конфигов не скачиваются.
+func UpdateIncludes(files []string, trusted func(path string) bool) ([]string, []error) {
+	r := newIncludeResolver(true, trusted)
+	r.resolve(files)
+
+	return r.updated, r.errs
+}
+
+type includeResolver struct {
+	seen map[string]bool
+	out  []string
+	errs []error
+
+	// update скачивать удаленные конфиги, даже если они есть в кэше
+	update  bool
+	updated []string
+
+	trusted func(path string) bool
+}
+
+func newIncludeResolver(update bool, trusted func(path string) bool) *includeResolver {
+	return &includeResolver{seen: make(map[string]bool), update: update, trusted: trusted}
+}
+
+func (r *includeResolver) resolve(files []string) {
+	for _, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			r.errs = append(r.errs, err)
+			continue
+		}
+
+		r.seen[abs] = true
+		r.walk([]string{abs})
+	}
+}
+
+// walk подключает конфиги из последнего файла цепочки chain.
+func (r *includeResolver) walk(chain []string) {
+	file := chain[len(chain)-1]
+
+	includes, err := readIncludes(file)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("failed to read includes of %s: %w", file, err))
+		return
+	}
+
+	for _, include := range includes {
+		paths, err := r.includePaths(include, filepath.Dir(file))
+		if err != nil {
+			r.errs = append(r.errs, fmt.Errorf("failed to include %q from %s: %w", include, file, err))
+			continue
+		}
+
+		for _, path := range paths {
+			if slices.Contains(chain, path) {
+				r.errs = append(r.errs, fmt.Errorf(
+					"%w: %s -> %s", ErrIncludeCycle, strings.Join(chain, " -> "), path,
+				))
+				continue
+			}
+
+			// NOTE: конфиг, подключенный несколькими файлами, загружаем один раз
+			if r.seen[path] {
+				logger.SaveDebugf("config %s already included; skip", path)
+				continue
+			}
+			r.seen[path] = true
+
+			logger.SaveDebugf("include %s from %s", path, file)
+			r.out = append(r.out, path)
+
+			// WARN: include недоверенного конфига могут подключить что угодно, даже разрешенный файл
+			if r.trusted != nil && !r.trusted(path) {
+				logger.SaveDebugf("config %s is not trusted; skip its includes", path)
+				continue
+			}
+			r.walk(append(slices.Clone(chain), path))
+		}
+	}
+}
+
+// fetch локальный путь удаленного конфига из кэша.
+func (r *includeResolver) fetch(include string) (string, error) {
+	source, err := remote.ParseSource(include)
+	if err != nil {
+		return "", err
+	}
+
+	// NOTE: несколько файлов одного репозитория обновляются одним скачиванием
+	if !r.update || slices.Contains(r.updated, source.Key()) {
+		return remote.Resolve(source)
+	}
+
+	path, err := remote.Update(source)
+	if err != nil {
+		return "", err
+	}
+	r.updated = append(r.updated, source.Key())
+
+	return path, nil
+}
+
+func readIncludes(file string) ([]string, error) {
+	v := viper.New()
+	UseConfigFile(v, file)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	return v.GetStringSlice(IncludeKey), nil
+}
+
+// includePaths абсолютные пути конфигов для одной записи include.
+func (r *includeResolver) includePaths(include string, base string) ([]string, error) {
+	optional := strings.HasPrefix(include, "?")
+	include = strings.TrimSpace(strings.TrimPrefix(include, "?"))
+	if include == "" {
+		return nil, errors.New("empty include path")
+	}
+
+	if remote.IsRemote(include) {
+		path, err := r.fetch(include)
+		if err != nil {
+			if optional {
+				logger.SaveDebugf("failed to fetch optional include %s: %v; skip", include, err)
+				return nil, nil
+			}
+			return nil, err
+		}
+		include = path
+	}
+
+	if strings.HasPrefix(include, "~") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, errors.New("failed to get user home dir")
+		}
+		include = strings.Replace(include, "~", home, 1)
+	}
+
+	if !filepath.IsAbs(include) {
+		include = filepath.Join(base, include)
+	}
+
+	matches := []string{include}
+	glob := strings.ContainsAny(include, "*?[")
+	if glob {
+		var err error
+		// NOTE: шаблон без совпадений не ошибка: например, пустая aliases.d
+		if matches, err = filepath.Glob(include); err != nil {
+			return nil, err
+		}
+	}
+
+	paths := make([]string, 0, len(matches))
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			if optional && errors.Is(err, os.ErrNotExist) {
+				logger.SaveDebugf("optional include %s not found; skip", match)
+				continue
+			}
+			return nil, err
+		}
+
+		if info.IsDir() {
+			path, ok := FindConfigIn(match, ".ali")
+			if !ok && (optional || glob) {
+				continue
+			}
+			if !ok {
+				path = filepath.Join(match, ".ali")
+			}
+			match = path
+		}
+		paths = append(paths, filepath.Clean(match))
+	}
+
+	return paths, nil
+}
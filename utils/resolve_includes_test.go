@@ -0,0 +1,82 @@
+package utils_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+func TestResolveIncludes(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"project/aliases.d", "shared"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files := map[string]string{
+		"project/.ali":            "include:\n  - aliases.d/*.yml\n  - ?local.yml\n  - ../shared\n",
+		"project/aliases.d/a.yml": "aliases:\n  a: echo a\n",
+		"project/aliases.d/b.yml": "include: [../../shared/.ali]\n",
+		"shared/.ali":             "include: [../project/.ali]\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(filepath.Join(root, path), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	includes, errs := utils.ResolveIncludes([]string{filepath.Join(root, "project", ".ali")}, nil)
+
+	expected := []string{
+		filepath.Join(root, "project", "aliases.d", "a.yml"),
+		filepath.Join(root, "project", "aliases.d", "b.yml"),
+		filepath.Join(root, "shared", ".ali"),
+	}
+	if !slices.Equal(includes, expected) {
+		t.Errorf("ERROR: want: %v; got: %v", expected, includes)
+	} else {
+		t.Logf("SUCCESS! got: %v", includes)
+	}
+
+	if len(errs) != 1 || !errors.Is(errs[0], utils.ErrIncludeCycle) {
+		t.Errorf("ERROR: want: one include cycle error; got: %v", errs)
+	} else {
+		t.Logf("SUCCESS! got: %v", errs[0])
+	}
+}
+
+func TestResolveIncludesTrusted(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		".ali":      "include: [team.yml]\n",
+		"team.yml":  "include:\n  - inner.yml\n  - https://example.com/evil.yml#sha256=00\n",
+		"inner.yml": "aliases:\n  inner: echo INNER-RAN\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(filepath.Join(root, path), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	untrusted := filepath.Join(root, "team.yml")
+	trusted := func(path string) bool { return path != untrusted }
+
+	includes, errs := utils.ResolveIncludes([]string{filepath.Join(root, ".ali")}, trusted)
+
+	// NOTE: недоверенный team.yml остается в списке, а inner.yml и удаленный конфиг не подключаются
+	expected := []string{untrusted}
+	if !slices.Equal(includes, expected) {
+		t.Errorf("ERROR: want: %v; got: %v", expected, includes)
+	} else {
+		t.Logf("SUCCESS! got: %v", includes)
+	}
+
+	if len(errs) != 0 {
+		t.Errorf("ERROR: want: no errors; got: %v", errs)
+	}
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/algrvvv/ali/logger"
+)
+
+// SSHExecutor выполняет команду на удаленном хосте через системный ssh.
+// Поэтому работают ~/.ssh/config (Host, User, Port, ProxyJump, ...), ssh-agent и known_hosts.
+type SSHExecutor struct {
+	// Host цель для ssh: `user@server` или хост из ~/.ssh/config
+	Host string
+	// TTY выделять удаленной команде терминал (ssh -t)
+	TTY bool
+}
+
+var envNameRegexp = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+func (e SSHExecutor) Command(command string, dir string, envs map[string]any) (*exec.Cmd, error) {
+	if strings.HasPrefix(e.Host, "-") {
+		return nil, fmt.Errorf("invalid ssh host: %q", e.Host)
+	}
+
+	remote, err := RemoteCommand(command, dir, envs)
+	if err != nil {
+		return nil, err
+	}
+
+	tty := "-T"
+	if e.TTY {
+		tty = "-t"
+	}
+
+	logger.SaveDebugf("remote command for %s: %s", e.Host, remote)
+	// NOTE: локальное окружение на удаленный хост не передается,
+	// только env алиаса внутри удаленной команды
+	return exec.Command("ssh", tty, e.Host, "--", remote), nil
+}
+
+// RemoteCommand собирает строку для удаленной оболочки: переход в dir,
+// переменные окружения и сама команда в sh -c. Все части экранируются,
+// поэтому команду можно писать так же, как для локального запуска.
+func RemoteCommand(command string, dir string, envs map[string]any) (string, error) {
+	var parts []string
+
+	if dir != "" && dir != "." {
+		parts = append(parts, "cd "+quoteRemoteDir(dir), "&&")
+	}
+
+	if len(envs) > 0 {
+		names := make([]string, 0, len(envs))
+		for name := range envs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		parts = append(parts, "env")
+		for _, name := range names {
+			n := strings.ToUpper(name)
+			if !envNameRegexp.MatchString(n) {
+				return "", fmt.Errorf("invalid env variable name: %q", name)
+			}
+
+			parts = append(parts, ShellQuote(fmt.Sprintf("%s=%v", n, envs[name])))
+		}
+	}
+
+	if strings.TrimSpace(command) == "" {
+		return "", errors.New("empty command")
+	}
+
+	parts = append(parts, "sh", "-c", ShellQuote(command))
+	return strings.Join(parts, " "), nil
+}
+
+// ShellQuote экранирует строку для POSIX оболочки одинарными кавычками.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// quoteRemoteDir экранирует директорию, оставляя ~ в начале, чтобы
+// его раскрыла удаленная оболочка в домашнюю директорию удаленного пользователя.
+func quoteRemoteDir(dir string) string {
+	switch {
+	case dir == "~":
+		return "~"
+	case strings.HasPrefix(dir, "~/"):
+		return "~/" + ShellQuote(dir[2:])
+	default:
+		return ShellQuote(dir)
+	}
+}
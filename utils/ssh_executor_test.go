@@ -0,0 +1,42 @@
+package utils_test
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+func TestRemoteCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("remote command is executed by POSIX sh")
+	}
+
+	tests := []struct {
+		command  string
+		dir      string
+		envs     map[string]any
+		expected string
+	}{
+		{command: `echo "it's ok"`, expected: "it's ok\n"},
+		{command: `echo $GREETING "$NAME"`, envs: map[string]any{"greeting": "hello", "name": "o'neil $HOME"}, expected: "hello o'neil $HOME\n"},
+		{command: "pwd", dir: "/tmp", expected: "/tmp\n"},
+	}
+
+	for _, test := range tests {
+		remote, err := utils.RemoteCommand(test.command, test.dir, test.envs)
+		if err != nil {
+			t.Errorf("ERROR: %s: %v", test.command, err)
+			continue
+		}
+
+		// удаленная оболочка получает строку целиком, как sh -c
+		out, err := exec.Command("sh", "-c", remote).Output()
+		if err != nil || string(out) != test.expected {
+			t.Errorf("ERROR: %s: want: %q; got: %q (%v)", remote, test.expected, out, err)
+		} else {
+			t.Logf("SUCCESS! %s: got: %q", remote, out)
+		}
+	}
+}
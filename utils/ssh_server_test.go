@@ -0,0 +1,174 @@
+//go:build !windows
+
+package utils_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+// startSSHServer поднимает ssh сервер на localhost, который выполняет exec запросы
+// через локальный sh. Возвращает путь до ssh_config с хостом `test`.
+func startSSHServer(t *testing.T) string {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSH(conn, config)
+		}
+	}()
+
+	sshConfig := filepath.Join(t.TempDir(), "ssh_config")
+	content := fmt.Sprintf(`Host test
+  HostName 127.0.0.1
+  Port %d
+  User ali
+  BatchMode yes
+  StrictHostKeyChecking no
+  UserKnownHostsFile /dev/null
+  LogLevel ERROR
+`, listener.Addr().(*net.TCPAddr).Port)
+
+	if err := os.WriteFile(sshConfig, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return sshConfig
+}
+
+func serveSSH(conn net.Conn, config *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "only session is supported")
+			continue
+		}
+
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go serveSession(channel, requests)
+	}
+}
+
+// serveSession выполняет exec запрос и возвращает клиенту код завершения.
+func serveSession(channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer channel.Close()
+
+	for req := range requests {
+		if req.Type != "exec" {
+			// окружение клиента (env, pty-req) сервер не принимает, как и sshd по умолчанию
+			_ = req.Reply(false, nil)
+			continue
+		}
+
+		var payload struct{ Command string }
+		if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		_ = req.Reply(true, nil)
+
+		cmd := exec.Command("sh", "-c", payload.Command)
+		cmd.Env = []string{"PATH=" + os.Getenv("PATH")}
+		cmd.Stdout = channel
+		cmd.Stderr = channel.Stderr()
+		code := utils.ExitCode(cmd.Run())
+
+		status := make([]byte, 4)
+		binary.BigEndian.PutUint32(status, uint32(code))
+		_, _ = channel.SendRequest("exit-status", false, status)
+		return
+	}
+}
+
+func TestSSHExecutor(t *testing.T) {
+	if _, err := exec.LookPath("ssh"); err != nil {
+		t.Skip("ssh is not installed")
+	}
+
+	sshConfig := startSSHServer(t)
+	dir := filepath.Join(t.TempDir(), "my project")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		command  string
+		dir      string
+		envs     map[string]any
+		expected string
+		code     int
+	}{
+		{name: "output", command: "echo 'it works'", expected: "it works"},
+		{name: "dir", command: "pwd", dir: dir, expected: dir},
+		{name: "env", command: `echo "$GREETING, $NAME"`, envs: map[string]any{"greeting": "hi there", "name": "ali"}, expected: "hi there, ali"},
+		{name: "exit code", command: "echo failed; exit 7", expected: "failed", code: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := utils.SSHExecutor{Host: "test"}.Command(tt.command, tt.dir, tt.envs)
+			if err != nil {
+				t.Fatalf("ERROR: command: %v", err)
+			}
+
+			// NOTE: адрес тестового сервера берется из своего ssh_config
+			cmd.Args = append([]string{cmd.Args[0], "-F", sshConfig}, cmd.Args[1:]...)
+
+			var stderr strings.Builder
+			cmd.Stderr = &stderr
+			out, err := cmd.Output()
+			code := utils.ExitCode(err)
+
+			if got := strings.TrimSpace(string(out)); got != tt.expected || code != tt.code {
+				t.Errorf("ERROR: want: %q (code %d); got: %q (code %d); stderr: %s",
+					tt.expected, tt.code, got, code, stderr.String())
+			} else {
+				t.Logf("SUCCESS! %s: %q (code %d)", tt.name, got, code)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/algrvvv/ali/schedule"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	scheduleType = reflect.TypeOf(schedule.Config{})
+)
+
+// checker проверяет узлы одного файла.
+type checker struct {
+	file   string
+	issues []Issue
+}
+
+func (c *checker) addf(node *yaml.Node, format string, args ...any) {
+	c.issues = append(c.issues, Issue{
+		File:    c.file,
+		Line:    node.Line,
+		Column:  node.Column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// checkNode проверяет, что значение node можно разобрать в тип t так же,
+// как это делает LoadAliases (mapstructure с WeaklyTypedInput).
+func (c *checker) checkNode(node *yaml.Node, t reflect.Type, path string) {
+	node = resolveAlias(node)
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == durationType:
+		if node.Kind != yaml.ScalarNode {
+			c.addf(node, "%s: want duration (for example 30s), got %s", path, kindName(node))
+		} else if _, err := strconv.ParseInt(node.Value, 10, 64); err != nil {
+			if _, err := time.ParseDuration(node.Value); err != nil {
+				c.addf(node, "%s: invalid duration %q", path, node.Value)
+			}
+		}
+		return
+	case t == scheduleType && node.Kind == yaml.ScalarNode:
+		cfg, err := schedule.ParseSpec(node.Value)
+		if err == nil {
+			err = cfg.Prepare()
+		}
+		if err != nil {
+			c.addf(node, "%s: %v", path, err)
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		c.expectScalar(node, path, "string")
+	case reflect.Bool:
+		if c.expectScalar(node, path, "bool") {
+			if _, err := strconv.ParseBool(node.Value); err != nil {
+				c.addf(node, "%s: want bool, got %q", path, node.Value)
+			}
+		}
+	case reflect.Int, reflect.Int64:
+		if c.expectScalar(node, path, "number") {
+			if _, err := strconv.ParseInt(node.Value, 0, 64); err != nil {
+				c.addf(node, "%s: want number, got %q", path, node.Value)
+			}
+		}
+	case reflect.Slice:
+		// NOTE: одно значение вместо списка mapstructure превращает в список из него
+		if node.Kind == yaml.ScalarNode {
+			c.checkNode(node, t.Elem(), path)
+			return
+		}
+		if node.Kind != yaml.SequenceNode {
+			c.addf(node, "%s: want list, got %s", path, kindName(node))
+			return
+		}
+		for i, item := range node.Content {
+			c.checkNode(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			c.addf(node, "%s: want mapping, got %s", path, kindName(node))
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			c.checkNode(node.Content[i+1], t.Elem(), path+"."+node.Content[i].Value)
+		}
+	case reflect.Struct:
+		c.checkStruct(node, t, path)
+	}
+}
+
+// checkStruct проверяет ключи и значения структуры с тегами mapstructure.
+func (c *checker) checkStruct(node *yaml.Node, t reflect.Type, path string) {
+	if node.Kind != yaml.MappingNode {
+		c.addf(node, "%s: want mapping, got %s", path, kindName(node))
+		return
+	}
+
+	fields := structFields(t)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		field, ok := fields[strings.ToLower(key.Value)]
+		if !ok {
+			c.addf(key, "%s: unknown key %q%s", path, key.Value, suggest(key.Value, fields))
+			continue
+		}
+
+		c.checkNode(value, field.Type, path+"."+key.Value)
+	}
+}
+
+// structFields поля структуры по тегу mapstructure, без внутренних (alias).
+func structFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if !field.IsExported() || name == "" || name == "-" || name == "alias" {
+			continue
+		}
+		fields[name] = field
+	}
+
+	return fields
+}
+
+// suggest подсказка для опечатки в ключе: parralel -> parallel.
+func suggest[T any](key string, known map[string]T) string {
+	best, bestDistance := "", 3
+	for name := range known {
+		if d := distance(strings.ToLower(key), name); d < bestDistance || d == bestDistance && name < best {
+			best, bestDistance = name, d
+		}
+	}
+
+	if best == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+// distance расстояние Левенштейна.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
+
+func (c *checker) expectScalar(node *yaml.Node, path string, want string) bool {
+	if node.Kind != yaml.ScalarNode {
+		c.addf(node, "%s: want %s, got %s", path, want, kindName(node))
+		return false
+	}
+
+	return true
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	return node
+}
+
+func kindName(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "mapping"
+	case yaml.SequenceNode:
+		return "list"
+	default:
+		return "value"
+	}
+}
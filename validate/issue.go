@@ -0,0 +1,50 @@
+package validate
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Issue ошибка в конфигурации с местом, где она найдена.
+type Issue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s", i.File, i.Message)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s", i.File, i.Line, i.Column, i.Message)
+}
+
+func issueAt(at location, format string, args ...any) Issue {
+	return Issue{
+		File:    at.file,
+		Line:    at.node.Line,
+		Column:  at.node.Column,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// sortIssues сортирует ошибки по порядку загрузки файлов и строкам в них.
+func sortIssues(issues []Issue, files []string) {
+	order := make(map[string]int, len(files))
+	for i, file := range files {
+		order[file] = i
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.File != b.File {
+			return order[a.File] < order[b.File]
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
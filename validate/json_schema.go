@@ -0,0 +1,222 @@
+package validate
+
+import (
+	"maps"
+	"reflect"
+	"sort"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+// SchemaURL версия JSON Schema, которую понимает yaml-language-server.
+const SchemaURL = "http://json-schema.org/draft-07/schema#"
+
+// описания ключей для подсказок в редакторе; типы берутся из Go структур
+var descriptions = map[string]string{
+	"aliases":              "Aliases: name -> command or alias settings",
+	"parallel":             "Detailed commands of parallel aliases: alias -> list of commands",
+	"vars":                 "Variables for {{name}} templates in commands",
+	"env":                  "Environment variables for all aliases",
+	"include":              "Other configs: paths, globs, ?optional, git+https://...//file@ref, https://...#sha256=",
+	"root":                 "Don't load .ali files from parent directories",
+	"app":                  "Application settings",
+	"notify":               "Notifications about finished aliases",
+	"app.editor":           "Editor for ali edit",
+	"app.profile":          "Default profile, overridden by ALI_PROFILE and --profile",
+	"profiles":             "Sets of vars and env: name -> {vars, env}, selected with --profile",
+	"profiles.vars":        "Variables of the profile, override vars",
+	"profiles.env":         "Environment variables of the profile, override env",
+	"group.desc":           "Description of the group for ali list",
+	"group.env":            "Environment variables of all aliases in the group",
+	"group.dir":            "Directory for all aliases in the group",
+	"group.host":           "Host for all aliases in the group",
+	"alias.aliases":        "Synonyms of the alias",
+	"alias.cmds":           "Commands of the alias",
+	"alias.desc":           "Description for ali list",
+	"alias.env":            "Environment variables of the alias",
+	"alias.parallel":       "Run commands in parallel",
+	"alias.dir":            "Directory to run commands in",
+	"alias.ui":             "UI for parallel commands: stream, tui or tmux",
+	"alias.tmux_layout":    "tmux layout for ui: tmux",
+	"alias.output":         "Output of parallel commands: stream or grouped",
+	"alias.timestamps":     "Prefix parallel output with time: wall or relative",
+	"alias.log_dir":        "Save parallel output to files (true - ~/.ali/runs)",
+	"alias.tty":            "Run commands in a pseudo-terminal",
+	"alias.host":           "Run commands over ssh: user@server or a host from ~/.ssh/config",
+	"alias.notify":         "Notifications about this alias",
+	"alias.schedule":       "Schedule for ali daemon: cron, @daily, @every 30m or 30m",
+	"command.label":        "Label in output",
+	"command.color":        "Color of the label",
+	"command.command":      "Command to run",
+	"command.path":         "Directory to run the command in",
+	"command.ready_when":   "Readiness probe: port, http, log or file",
+	"command.start_after":  "Labels of commands that must be ready first",
+	"command.restart":      "Restart policy: no, on-failure or always",
+	"command.max_restarts": "Maximum restarts, 0 - without limit",
+	"command.backoff":      "Delay before restart, doubles after each restart",
+	"command.tty":          "Run the command in a pseudo-terminal",
+	"command.host":         "Run the command over ssh",
+	"notify.after":         "Only notify about runs longer than this",
+	"notify.on":            "When to notify: success, failure",
+	"notify.escape":        "Terminal escape sequence: osc9, osc777 or none",
+	"notify.bell":          "Ring the terminal bell",
+	"notify.command":       "Notifier command, gets ALI_ALIAS, ALI_EXIT_CODE, ALI_MESSAGE and more",
+	"ready_when.port":      "TCP port is open",
+	"ready_when.host":      "Host for port, localhost by default",
+	"ready_when.http":      "URL returns 200",
+	"ready_when.log":       "Regular expression in the command output",
+	"ready_when.file":      "File exists",
+	"ready_when.timeout":   "How long to wait",
+	"ready_when.interval":  "How often to check",
+	"schedule.cron":        "Cron expression",
+	"schedule.every":       "Interval between runs",
+	"schedule.missed":      "Missed runs while the daemon was stopped: skip or run",
+}
+
+// форматы значений, которые шире, чем Go тип
+var overrides = map[string]map[string]any{
+	// log_dir: true - директория по умолчанию
+	"alias.log_dir": {"type": []string{"string", "boolean"}},
+}
+
+// Schema JSON Schema конфигов ali, построенная по тем же типам, что и проверка.
+func Schema() map[string]any {
+	properties := make(map[string]any, len(topLevel))
+	for name, t := range topLevel {
+		switch name {
+		case "aliases":
+			properties[name] = map[string]any{
+				"type":                 "object",
+				"additionalProperties": map[string]any{"$ref": aliasRef},
+			}
+		case "parallel":
+			command := typeSchema(commandType, "command")
+			command["required"] = []string{"command"}
+			properties[name] = map[string]any{
+				"type":                 "object",
+				"additionalProperties": map[string]any{"type": "array", "items": command},
+			}
+		default:
+			properties[name] = typeSchema(t, name)
+		}
+
+		describe(properties[name].(map[string]any), name)
+	}
+
+	// NOTE: группы вложенные, поэтому алиас описан отдельно и ссылается сам на себя
+	group := typeSchema(groupType, "group")
+	group["additionalProperties"] = map[string]any{"$ref": aliasRef}
+	group["not"] = map[string]any{
+		"anyOf": []any{
+			map[string]any{"required": []string{"cmds"}},
+			map[string]any{"required": []string{"parallel"}},
+		},
+	}
+	group["propertyNames"] = map[string]any{"not": map[string]any{"enum": utils.ReservedNames()}}
+	group["description"] = "Group of aliases: ali <group> <alias> or ali <group>:<alias>"
+
+	return map[string]any{
+		"$schema":              SchemaURL,
+		"title":                "ali config",
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+		"definitions": map[string]any{
+			"alias": map[string]any{
+				"anyOf": []any{
+					map[string]any{"type": "string", "minLength": 1, "description": "Command"},
+					typeSchema(aliasEntryType, "alias"),
+					group,
+				},
+			},
+		},
+	}
+}
+
+const aliasRef = "#/definitions/alias"
+
+// typeSchema схема значения Go типа; scope - префикс ключей в descriptions.
+func typeSchema(t reflect.Type, scope string) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == durationType:
+		return map[string]any{
+			"type":    []string{"string", "integer"},
+			"pattern": `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`,
+		}
+	case t == scheduleType:
+		return map[string]any{
+			"anyOf": []any{map[string]any{"type": "string"}, structSchema(t, "schedule")},
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Slice:
+		// NOTE: как и mapstructure, одно значение принимаем вместо списка
+		item := typeSchema(t.Elem(), scope)
+		return map[string]any{
+			"anyOf": []any{item, map[string]any{"type": "array", "items": item}},
+		}
+	case reflect.Map:
+		if t.Elem().Kind() == reflect.Interface {
+			return map[string]any{"type": "object"}
+		}
+		if t.Elem().Kind() == reflect.Struct {
+			return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem(), scope)}
+		}
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": map[string]any{"type": []string{"string", "number", "boolean"}},
+		}
+	case reflect.Struct:
+		return structSchema(t, scope)
+	default:
+		return map[string]any{}
+	}
+}
+
+func structSchema(t reflect.Type, scope string) map[string]any {
+	fields := structFields(t)
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	properties := make(map[string]any, len(fields))
+	for _, name := range names {
+		key := scope + "." + name
+
+		schema, ok := overrides[key]
+		if ok {
+			schema = maps.Clone(schema)
+		} else {
+			// вложенные структуры описываются по имени поля: notify, ready_when, schedule
+			schema = typeSchema(fields[name].Type, name)
+		}
+		describe(schema, key)
+		properties[name] = schema
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+func describe(schema map[string]any, key string) {
+	if desc, ok := descriptions[key]; ok {
+		schema["description"] = desc
+	}
+}
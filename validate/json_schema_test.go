@@ -0,0 +1,46 @@
+package validate_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/algrvvv/ali/parallel"
+	"github.com/algrvvv/ali/utils"
+	"github.com/algrvvv/ali/validate"
+)
+
+func TestSchema(t *testing.T) {
+	schema := validate.Schema()
+	properties := schema["properties"].(map[string]any)
+
+	aliasSchemas := schema["definitions"].(map[string]any)["alias"].(map[string]any)["anyOf"].([]any)
+	alias, group := aliasSchemas[1], aliasSchemas[2]
+	command := properties["parallel"].(map[string]any)["additionalProperties"].(map[string]any)["items"]
+
+	tests := []struct {
+		name   string
+		schema any
+		t      reflect.Type
+	}{
+		{name: "alias", schema: alias, t: reflect.TypeOf(utils.AliasEntry{})},
+		{name: "group", schema: group, t: reflect.TypeOf(utils.AliasGroup{})},
+		{name: "parallel command", schema: command, t: reflect.TypeOf(parallel.Command{})},
+	}
+
+	for _, test := range tests {
+		keys := test.schema.(map[string]any)["properties"].(map[string]any)
+
+		for i := 0; i < test.t.NumField(); i++ {
+			name, _, _ := strings.Cut(test.t.Field(i).Tag.Get("mapstructure"), ",")
+			if name == "" || name == "-" || name == "alias" {
+				continue
+			}
+
+			if _, ok := keys[name]; !ok {
+				t.Errorf("ERROR: %s: key %q is missing in schema", test.name, name)
+			}
+		}
+		t.Logf("SUCCESS! %s: %d keys", test.name, len(keys))
+	}
+}
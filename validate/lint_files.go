@@ -0,0 +1,262 @@
+package validate
+
+import (
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/algrvvv/ali/parallel"
+	"github.com/algrvvv/ali/utils"
+)
+
+var (
+	lintVarRegexp = regexp.MustCompile(`\{\{(\w+)\}\}`)
+	lintEnvRegexp = regexp.MustCompile(`\$\{?([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+// Lint ищет в конфигах то, что не мешает запуску, но скорее всего ошибка:
+// неиспользуемые vars и env, неизвестные {{var}} в командах и локальные алиасы,
+// которые перекрывают глобальные. global - глобальный конфиг среди files.
+func Lint(global string, files []string) []Issue {
+	l := &linter{globalAliases: make(map[string]location)}
+	for _, file := range files {
+		l.file(file, file == global)
+	}
+
+	issues := l.issues()
+	sortIssues(issues, files)
+
+	return issues
+}
+
+type declaration struct {
+	name  string
+	alias string
+	// group alias - имя группы
+	group bool
+	at    location
+}
+
+type command struct {
+	alias string
+	text  string
+	at    location
+}
+
+type linter struct {
+	vars          []declaration
+	envs          []declaration
+	commands      []command
+	globalAliases map[string]location
+	overrides     []declaration
+}
+
+func (l *linter) file(file string, global bool) {
+	doc, err := parseFile(file)
+	if err != nil || len(doc.Content) == 0 {
+		// NOTE: ошибки разбора показывает ali validate
+		return
+	}
+
+	root := resolveAlias(doc.Content[0])
+	if root.Kind != yaml.MappingNode {
+		return
+	}
+
+	eachKey(root, func(key, value *yaml.Node) {
+		switch strings.ToLower(key.Value) {
+		case "vars":
+			eachKey(value, func(name, _ *yaml.Node) {
+				l.vars = append(l.vars, declaration{name: strings.ToLower(name.Value), at: location{file, name}})
+			})
+		case "env":
+			eachKey(value, func(name, _ *yaml.Node) {
+				l.envs = append(l.envs, declaration{name: strings.ToUpper(name.Value), at: location{file, name}})
+			})
+		case utils.ProfilesKey:
+			// NOTE: переменные профиля тоже объявления, их можно использовать в командах
+			eachKey(value, func(_, profile *yaml.Node) {
+				eachKey(profile, func(key, value *yaml.Node) {
+					switch strings.ToLower(key.Value) {
+					case "vars":
+						eachKey(value, func(name, _ *yaml.Node) {
+							l.vars = append(l.vars, declaration{name: strings.ToLower(name.Value), at: location{file, name}})
+						})
+					case "env":
+						eachKey(value, func(name, _ *yaml.Node) {
+							l.envs = append(l.envs, declaration{name: strings.ToUpper(name.Value), at: location{file, name}})
+						})
+					}
+				})
+			})
+		case "aliases":
+			eachKey(value, func(name, entry *yaml.Node) {
+				l.alias(file, global, "", name, entry)
+			})
+		case parallel.ParallelPrefix:
+			eachKey(value, func(name, list *yaml.Node) {
+				alias := strings.ToLower(name.Value)
+				if list.Kind != yaml.SequenceNode {
+					return
+				}
+				for _, item := range list.Content {
+					eachKey(resolveAlias(item), func(key, value *yaml.Node) {
+						if strings.EqualFold(key.Value, "command") {
+							l.addCommand(alias, file, value)
+						}
+					})
+				}
+			})
+		}
+	})
+}
+
+func (l *linter) alias(file string, global bool, prefix string, name, entry *yaml.Node) {
+	alias := prefix + strings.ToLower(name.Value)
+
+	entry = resolveAlias(entry)
+	if entry.Kind == yaml.MappingNode && isGroup(entry) {
+		l.group(file, global, alias, entry)
+		return
+	}
+
+	if global {
+		l.globalAliases[alias] = location{file, name}
+	} else {
+		l.overrides = append(l.overrides, declaration{name: alias, at: location{file, name}})
+	}
+
+	if entry.Kind == yaml.ScalarNode {
+		l.addCommand(alias, file, entry)
+		return
+	}
+
+	eachKey(entry, func(key, value *yaml.Node) {
+		switch strings.ToLower(key.Value) {
+		case "cmds":
+			if value.Kind == yaml.SequenceNode {
+				for _, item := range value.Content {
+					l.addCommand(alias, file, item)
+				}
+				return
+			}
+			l.addCommand(alias, file, value)
+		case "env":
+			eachKey(value, func(env, _ *yaml.Node) {
+				l.envs = append(l.envs, declaration{name: strings.ToUpper(env.Value), alias: alias, at: location{file, env}})
+			})
+		}
+	})
+}
+
+// group собирает алиасы группы; env группы относится ко всем ее алиасам.
+func (l *linter) group(file string, global bool, group string, node *yaml.Node) {
+	eachKey(node, func(key, value *yaml.Node) {
+		// NOTE: такие имена алиасов не загружаются, о них сообщает ali validate
+		if utils.IsReservedName(key.Value) {
+			return
+		}
+
+		if !utils.IsGroupField(key.Value) {
+			l.alias(file, global, group+utils.GroupSeparator, key, value)
+			return
+		}
+
+		if strings.EqualFold(key.Value, "env") {
+			eachKey(value, func(env, _ *yaml.Node) {
+				l.envs = append(l.envs, declaration{
+					name: strings.ToUpper(env.Value), alias: group, group: true, at: location{file, env},
+				})
+			})
+		}
+	})
+}
+
+func (l *linter) addCommand(alias string, file string, node *yaml.Node) {
+	node = resolveAlias(node)
+	if node.Kind != yaml.ScalarNode {
+		return
+	}
+
+	l.commands = append(l.commands, command{alias: alias, text: node.Value, at: location{file, node}})
+}
+
+func (l *linter) issues() []Issue {
+	declared := make(map[string]bool)
+	for _, v := range l.vars {
+		declared[v.name] = true
+	}
+
+	usedVars := make(map[string]bool)
+	// usedEnvs переменные окружения по алиасам, в командах которых они используются
+	usedEnvs := make(map[string]map[string]bool)
+	var out []Issue
+	for _, c := range l.commands {
+		for _, match := range lintVarRegexp.FindAllStringSubmatch(c.text, -1) {
+			name := strings.ToLower(match[1])
+			usedVars[name] = true
+			if !declared[name] {
+				out = append(out, issueAt(c.at, "alias %q uses undefined var {{%s}}", c.alias, match[1]))
+			}
+		}
+
+		for _, match := range lintEnvRegexp.FindAllStringSubmatch(c.text, -1) {
+			if usedEnvs[match[1]] == nil {
+				usedEnvs[match[1]] = make(map[string]bool)
+			}
+			usedEnvs[match[1]][c.alias] = true
+		}
+	}
+
+	for _, v := range l.vars {
+		if !usedVars[v.name] {
+			out = append(out, issueAt(v.at, "var %q is not used in any command", v.name))
+		}
+	}
+
+	for _, env := range l.envs {
+		used := len(usedEnvs[env.name]) > 0
+		if env.group {
+			used = false
+			for alias := range usedEnvs[env.name] {
+				if strings.HasPrefix(alias, env.alias+utils.GroupSeparator) {
+					used = true
+					break
+				}
+			}
+		} else if env.alias != "" {
+			used = usedEnvs[env.name][env.alias]
+		}
+
+		switch {
+		case used:
+		case env.alias == "":
+			out = append(out, issueAt(env.at, "env %q is not referenced in any command", env.name))
+		case env.group:
+			out = append(out, issueAt(env.at, "env %q is not referenced in commands of group %q", env.name, env.alias))
+		default:
+			out = append(out, issueAt(env.at, "env %q is not referenced in commands of alias %q", env.name, env.alias))
+		}
+	}
+
+	for _, alias := range l.overrides {
+		if at, ok := l.globalAliases[alias.name]; ok {
+			out = append(out, issueAt(alias.at, "alias %q overrides global alias (%s)", alias.name, at))
+		}
+	}
+
+	return out
+}
+
+// eachKey вызывает fn для каждой пары ключ-значение mapping узла.
+func eachKey(node *yaml.Node, fn func(key, value *yaml.Node)) {
+	node = resolveAlias(node)
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		fn(node.Content[i], resolveAlias(node.Content[i+1]))
+	}
+}
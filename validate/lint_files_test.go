@@ -0,0 +1,57 @@
+package validate_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/algrvvv/ali/validate"
+)
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+
+	global := filepath.Join(dir, "config.yml")
+	local := filepath.Join(dir, ".ali")
+	files := map[string]string{
+		global: "aliases:\n  build: go build ./...\n",
+		local: `vars:
+  user: admin
+  unused: x
+env:
+  TOKEN: secret
+aliases:
+  build: make
+  login:
+    env:
+      HOST: localhost
+      PORT: 80
+    cmds:
+      - ssh {{user}}@$HOST -p {{port}} -o "token=$TOKEN"
+`,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{
+		local + `:3:3: var "unused" is not used in any command`,
+		local + `:7:3: alias "build" overrides global alias (` + global + `:2)`,
+		local + `:11:7: env "PORT" is not referenced in commands of alias "login"`,
+		local + `:13:9: alias "login" uses undefined var {{port}}`,
+	}
+
+	var got []string
+	for _, issue := range validate.Lint(global, []string{global, local}) {
+		got = append(got, issue.String())
+	}
+
+	if !slices.Equal(got, expected) {
+		t.Errorf("ERROR: want: %v; got: %v", expected, got)
+	} else {
+		t.Logf("SUCCESS! got: %v", got)
+	}
+}
@@ -0,0 +1,41 @@
+package validate
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/algrvvv/ali/utils"
+)
+
+// parseFile разбирает конфиг любого поддерживаемого формата в дерево yaml.
+// Json - подмножество yaml и сохраняет номера строк, toml переводится в yaml
+// через map, поэтому строки в ошибках для него не указываются.
+func parseFile(file string) (*yaml.Node, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	typ := utils.ConfigType(file)
+
+	if typ != utils.TomlConfigurationType {
+		err = yaml.Unmarshal(data, &doc)
+		if err == nil || typ == utils.YamlConfigurationType {
+			return &doc, err
+		}
+		// NOTE: json с табами в отступах yaml не разбирает, читаем его как json
+	}
+
+	config, err := utils.DecodeConfig(data, typ)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := doc.Encode(config); err != nil {
+		return nil, err
+	}
+
+	return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&doc}}, nil
+}
@@ -0,0 +1,375 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/algrvvv/ali/notify"
+	"github.com/algrvvv/ali/parallel"
+	"github.com/algrvvv/ali/utils"
+)
+
+type appConfig struct {
+	Editor  string `mapstructure:"editor"`
+	Profile string `mapstructure:"profile"`
+}
+
+// ключи верхнего уровня конфига; aliases и parallel проверяются отдельно
+var topLevel = map[string]reflect.Type{
+	"aliases":               nil,
+	parallel.ParallelPrefix: nil,
+	"vars":                  reflect.TypeOf(map[string]string{}),
+	"env":                   reflect.TypeOf(map[string]any{}),
+	utils.IncludeKey:        reflect.TypeOf([]string{}),
+	utils.RootConfigKey:     reflect.TypeOf(false),
+	utils.StrictKey:         reflect.TypeOf(false),
+	utils.ProfilesKey:       reflect.TypeOf(map[string]utils.Profile{}),
+	"app":                   reflect.TypeOf(appConfig{}),
+	"notify":                reflect.TypeOf(notify.Config{}),
+}
+
+var (
+	aliasEntryType = reflect.TypeOf(utils.AliasEntry{})
+	commandType    = reflect.TypeOf(parallel.Command{})
+	groupType      = reflect.TypeOf(utils.AliasGroup{})
+)
+
+// Files проверяет конфиги в порядке загрузки: каждый файл отдельно
+// и алиасы всех файлов вместе (команды, пересечения синонимов).
+func Files(files []string) []Issue {
+	v := &validator{
+		aliases:  make(map[string]location),
+		hasCmds:  make(map[string]bool),
+		synonyms: make(map[string]synonym),
+	}
+
+	for _, file := range files {
+		v.file(file)
+	}
+	v.crossCheck()
+
+	sortIssues(v.issues, files)
+	return v.issues
+}
+
+type location struct {
+	file string
+	node *yaml.Node
+}
+
+func (l location) String() string {
+	return fmt.Sprintf("%s:%d", l.file, l.node.Line)
+}
+
+type synonym struct {
+	name  string
+	alias string
+	at    location
+}
+
+type validator struct {
+	issues []Issue
+
+	// aliases первое объявление каждого алиаса
+	aliases    map[string]location
+	aliasOrder []string
+	hasCmds    map[string]bool
+	// synonyms первое использование каждого синонима
+	synonyms    map[string]synonym
+	synonymList []synonym
+}
+
+func (v *validator) file(file string) {
+	c := &checker{file: file}
+	defer func() { v.issues = append(v.issues, c.issues...) }()
+
+	doc, err := parseFile(file)
+	if err != nil {
+		c.issues = append(c.issues, Issue{File: file, Message: err.Error()})
+		return
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return
+	}
+
+	root := resolveAlias(doc.Content[0])
+	if root.Kind == yaml.ScalarNode && root.Tag == "!!null" {
+		return
+	}
+	if root.Kind != yaml.MappingNode {
+		c.addf(root, "config: want mapping, got %s", kindName(root))
+		return
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], resolveAlias(root.Content[i+1])
+		name := strings.ToLower(key.Value)
+
+		t, ok := topLevel[name]
+		if !ok {
+			c.addf(key, "unknown key %q%s", key.Value, suggest(key.Value, topLevel))
+			continue
+		}
+
+		switch name {
+		case "aliases":
+			v.checkAliases(c, value)
+		case parallel.ParallelPrefix:
+			v.checkParallel(c, value)
+		default:
+			c.checkNode(value, t, key.Value)
+		}
+	}
+}
+
+func (v *validator) checkAliases(c *checker, node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		if node.Tag != "!!null" {
+			c.addf(node, "aliases: want mapping, got %s", kindName(node))
+		}
+		return
+	}
+
+	v.checkAliasMap(c, node, "", "aliases")
+}
+
+// checkAliasMap проверяет алиасы группы; prefix - префикс имен алиасов группы (db:).
+func (v *validator) checkAliasMap(c *checker, node *yaml.Node, prefix, parent string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], resolveAlias(node.Content[i+1])
+		alias := prefix + strings.ToLower(key.Value)
+		path := parent + "." + key.Value
+
+		switch value.Kind {
+		case yaml.ScalarNode:
+			v.declare(alias, location{file: c.file, node: key})
+			if strings.TrimSpace(value.Value) == "" {
+				c.addf(value, "%s: empty command", path)
+				continue
+			}
+			v.hasCmds[alias] = true
+		case yaml.MappingNode:
+			if isGroup(value) {
+				v.checkGroup(c, value, alias, path)
+				continue
+			}
+
+			v.declare(alias, location{file: c.file, node: key})
+			c.checkStruct(value, aliasEntryType, path)
+			v.collectAlias(c, alias, value, path)
+		default:
+			v.declare(alias, location{file: c.file, node: key})
+			c.addf(value, "%s: want command or mapping, got %s", path, kindName(value))
+		}
+	}
+}
+
+// checkGroup проверяет настройки группы и ее алиасы.
+func (v *validator) checkGroup(c *checker, node *yaml.Node, group, path string) {
+	fields := structFields(groupType)
+	children := &yaml.Node{Kind: yaml.MappingNode}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if utils.IsReservedName(key.Value) {
+			c.addf(key, "%s: %q is not supported in group (use: %s) and can't be an alias name",
+				path, key.Value, strings.Join(utils.GroupFields, ", "))
+			continue
+		}
+
+		if !utils.IsGroupField(key.Value) {
+			children.Content = append(children.Content, key, value)
+			continue
+		}
+
+		field := fields[strings.ToLower(key.Value)]
+		c.checkNode(value, field.Type, path+"."+key.Value)
+
+		// NOTE: desc, env, dir и host всегда настройки группы, а не ее алиасы
+		value = resolveAlias(value)
+		if strings.EqualFold(key.Value, "host") && value.Kind == yaml.ScalarNode && strings.ContainsAny(value.Value, " \t") {
+			c.addf(value, "%s.%s: %q looks like a command, not a host; group can't have an alias named %s",
+				path, key.Value, value.Value, key.Value)
+		}
+	}
+
+	// WARN: deploy: {desc: x, cmd: make} без cmds молча становится группой с алиасом deploy:cmd
+	if typos := settingTypos(children); typos != nil {
+		for i := 0; i+1 < len(children.Content); i += 2 {
+			key := children.Content[i]
+			c.addf(key, "%s: %q looks like a typo of alias setting %q; without cmds this is a group, not an alias",
+				path, key.Value, typos[i/2])
+		}
+	}
+
+	v.checkAliasMap(c, children, group+utils.GroupSeparator, path)
+}
+
+// settingTypos настройки алиаса, опечатками которых выглядят все ключи группы,
+// или nil, если хотя бы один ключ похож на обычное имя алиаса.
+func settingTypos(children *yaml.Node) []string {
+	fields := structFields(aliasEntryType)
+	typos := make([]string, 0, len(children.Content)/2)
+	for i := 0; i+1 < len(children.Content); i += 2 {
+		key := strings.ToLower(children.Content[i].Value)
+		// NOTE: короткие имена (up, ls) слишком часто совпадают с настройками на одну букву
+		if len(key) < 3 {
+			return nil
+		}
+
+		typo := ""
+		for name := range fields {
+			if distance(key, name) == 1 && (typo == "" || name < typo) {
+				typo = name
+			}
+		}
+		if typo == "" {
+			return nil
+		}
+		typos = append(typos, typo)
+	}
+
+	if len(typos) == 0 {
+		return nil
+	}
+
+	return typos
+}
+
+// isGroup группа алиасов, а не описание алиаса.
+func isGroup(node *yaml.Node) bool {
+	keys := make([]string, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+
+	return utils.IsAliasGroup(keys)
+}
+
+// collectAlias запоминает команды и синонимы алиаса для общих проверок.
+func (v *validator) collectAlias(c *checker, alias string, node *yaml.Node, path string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], resolveAlias(node.Content[i+1])
+
+		switch strings.ToLower(key.Value) {
+		case "cmds":
+			// NOTE: пустой список команд покажет общая проверка алиаса
+			items := []*yaml.Node{value}
+			if value.Kind == yaml.SequenceNode {
+				items = value.Content
+			}
+
+			for _, item := range items {
+				item = resolveAlias(item)
+				if item.Kind != yaml.ScalarNode {
+					continue
+				}
+				if strings.TrimSpace(item.Value) == "" {
+					c.addf(item, "%s.cmds: empty command", path)
+					continue
+				}
+				v.hasCmds[alias] = true
+			}
+		case "aliases":
+			items := []*yaml.Node{value}
+			if value.Kind == yaml.SequenceNode {
+				items = value.Content
+			}
+
+			for _, item := range items {
+				item = resolveAlias(item)
+				if item.Kind != yaml.ScalarNode || item.Tag == "!!null" {
+					continue
+				}
+
+				s := synonym{name: strings.ToLower(item.Value), alias: alias, at: location{file: c.file, node: item}}
+				v.synonymList = append(v.synonymList, s)
+				if _, ok := v.synonyms[s.name]; !ok {
+					v.synonyms[s.name] = s
+				}
+			}
+		}
+	}
+}
+
+func (v *validator) checkParallel(c *checker, node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		if node.Tag != "!!null" {
+			c.addf(node, "%s: want mapping, got %s", parallel.ParallelPrefix, kindName(node))
+		}
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], resolveAlias(node.Content[i+1])
+		alias := strings.ToLower(key.Value)
+		path := parallel.ParallelPrefix + "." + key.Value
+		v.declare(alias, location{file: c.file, node: key})
+
+		if value.Kind != yaml.SequenceNode {
+			c.addf(value, "%s: want list of commands, got %s", path, kindName(value))
+			continue
+		}
+
+		for j, item := range value.Content {
+			item = resolveAlias(item)
+			itemPath := fmt.Sprintf("%s[%d]", path, j)
+			c.checkStruct(item, commandType, itemPath)
+
+			if item.Kind == yaml.MappingNode && !hasValue(item, "command") {
+				c.addf(item, "%s: empty command", itemPath)
+				continue
+			}
+			v.hasCmds[alias] = true
+		}
+	}
+}
+
+func (v *validator) declare(alias string, at location) {
+	if _, ok := v.aliases[alias]; ok {
+		return
+	}
+
+	v.aliases[alias] = at
+	v.aliasOrder = append(v.aliasOrder, alias)
+}
+
+// crossCheck проверки алиасов из всех файлов вместе:
+// в одном файле может быть синоним, а в другом - алиас с тем же именем.
+func (v *validator) crossCheck() {
+	for _, alias := range v.aliasOrder {
+		if !v.hasCmds[alias] {
+			at := v.aliases[alias]
+			v.add(at, "alias %q has no commands", alias)
+		}
+	}
+
+	for _, s := range v.synonymList {
+		if first := v.synonyms[s.name]; first.alias != s.alias {
+			v.add(s.at, "synonym %q of alias %q is already used by alias %q (%s)", s.name, s.alias, first.alias, first.at)
+			continue
+		}
+
+		if at, ok := v.aliases[s.name]; ok && s.name != s.alias {
+			v.add(s.at, "synonym %q of alias %q shadows alias %q (%s)", s.name, s.alias, s.name, at)
+		}
+	}
+}
+
+func (v *validator) add(at location, format string, args ...any) {
+	v.issues = append(v.issues, issueAt(at, format, args...))
+}
+
+func hasValue(node *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, key) {
+			return strings.TrimSpace(resolveAlias(node.Content[i+1]).Value) != ""
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,133 @@
+package validate_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/algrvvv/ali/validate"
+)
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	global := filepath.Join(dir, "config.yml")
+	local := filepath.Join(dir, ".ali")
+	files := map[string]string{
+		global: "aliases:\n  build: go build ./...\n  test:\n    aliases: [t]\n    cmds: [go test ./...]\n",
+		local: strings.Join([]string{
+			"aliases:",
+			"  dev:",
+			"    parralel: true",
+			"    aliases: [t, build]",
+			"    cmds:",
+			"      - npm run dev",
+			"  lint:",
+			"    tty: maybe",
+			"    cmds: []",
+			"",
+		}, "\n"),
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	issues := validate.Files([]string{global, local})
+
+	expected := []string{
+		local + `:3:5: aliases.dev: unknown key "parralel" (did you mean "parallel"?)`,
+		local + `:4:15: synonym "t" of alias "dev" is already used by alias "test" (` + global + `:4)`,
+		local + `:4:18: synonym "build" of alias "dev" shadows alias "build" (` + global + `:2)`,
+		local + `:7:3: alias "lint" has no commands`,
+		local + `:8:10: aliases.lint.tty: want bool, got "maybe"`,
+	}
+
+	if len(issues) != len(expected) {
+		t.Fatalf("ERROR: want: %d issues; got: %v", len(expected), issues)
+	}
+
+	for i, issue := range issues {
+		if issue.String() != expected[i] {
+			t.Errorf("ERROR: want: %s; got: %s", expected[i], issue)
+		} else {
+			t.Logf("SUCCESS! got: %s", issue)
+		}
+	}
+}
+
+func TestFilesGroupReservedNames(t *testing.T) {
+	local := filepath.Join(t.TempDir(), ".ali")
+	content := strings.Join([]string{
+		"aliases:",
+		"  docker:",
+		"    desc: docker tasks",
+		"    up: echo compose up",
+		"    ui: echo open ui",
+		"    host: echo host alias",
+		"  db:",
+		"    host: deploy@db.example.com",
+		"    migrate: migrate up",
+		"",
+	}, "\n")
+	if err := os.WriteFile(local, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := validate.Files([]string{local})
+
+	expected := []string{
+		local + `:5:5: aliases.docker: "ui" is not supported in group (use: desc, env, dir, host) and can't be an alias name`,
+		local + `:6:11: aliases.docker.host: "echo host alias" looks like a command, not a host; group can't have an alias named host`,
+	}
+
+	if len(issues) != len(expected) {
+		t.Fatalf("ERROR: want: %d issues; got: %v", len(expected), issues)
+	}
+
+	for i, issue := range issues {
+		if issue.String() != expected[i] {
+			t.Errorf("ERROR: want: %s; got: %s", expected[i], issue)
+		} else {
+			t.Logf("SUCCESS! got: %s", issue)
+		}
+	}
+}
+
+func TestFilesGroupSettingTypos(t *testing.T) {
+	local := filepath.Join(t.TempDir(), ".ali")
+	content := strings.Join([]string{
+		"aliases:",
+		"  deploy:",
+		"    desc: deploy app",
+		"    cmd: make deploy",
+		"  db:",
+		"    desc: db tasks",
+		"    cmd: psql",
+		"    migrate: migrate up",
+		"",
+	}, "\n")
+	if err := os.WriteFile(local, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := validate.Files([]string{local})
+
+	expected := []string{
+		local + `:4:5: aliases.deploy: "cmd" looks like a typo of alias setting "cmds"; without cmds this is a group, not an alias`,
+	}
+
+	if len(issues) != len(expected) {
+		t.Fatalf("ERROR: want: %d issues; got: %v", len(expected), issues)
+	}
+
+	for i, issue := range issues {
+		if issue.String() != expected[i] {
+			t.Errorf("ERROR: want: %s; got: %s", expected[i], issue)
+		} else {
+			t.Logf("SUCCESS! got: %s", issue)
+		}
+	}
+}